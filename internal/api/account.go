@@ -0,0 +1,33 @@
+package api
+
+import (
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/response"
+	"net/http"
+)
+
+type AccountAPI api
+
+func (a *AccountAPI) Details(w http.ResponseWriter, r *http.Request) {
+	login, _ := r.Context().Value("login").(string)
+	organizations, _ := r.Context().Value("organization").([]string)
+	_, isServiceAccount := r.Context().Value("role").(string)
+
+	if organizations == nil {
+		organizations = []string{}
+	}
+
+	resp := &models.AccountResponse{
+		Data: models.AccountDataResponse{
+			ID:   login,
+			Type: "users",
+			Attributes: models.AccountAttributesResponse{
+				Username:         login,
+				IsServiceAccount: isServiceAccount,
+				Organizations:    organizations,
+			},
+		},
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
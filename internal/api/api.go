@@ -3,6 +3,8 @@ package api
 import (
 	"go-terraform-registry/internal/backend"
 	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/eventstream"
 	"go-terraform-registry/internal/storage"
 )
 
@@ -10,4 +12,6 @@ type api struct {
 	Config  registryconfig.RegistryConfig
 	Backend backend.Backend
 	Storage storage.RegistryProviderStorage
+	Events  *events.Bus
+	Broker  *eventstream.Broker
 }
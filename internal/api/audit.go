@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+)
+
+type AuditAPI api
+
+func (a *AuditAPI) List(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.AuditParameters{
+		Organization: chi.URLParam(r, "organization"),
+		PageNumber:   1,
+		PageSize:     defaultAuditPageSize,
+	}
+
+	query := r.URL.Query()
+
+	var err error
+	parameters.Since, err = queryTime(query, "since")
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	parameters.Until, err = queryTime(query, "until")
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if query.Has("page[number]") {
+		val, err := strconv.Atoi(query.Get("page[number]"))
+		if err != nil || val < 1 {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "page[number] must be a positive number",
+			})
+			return
+		}
+		parameters.PageNumber = val
+	}
+
+	if query.Has("page[size]") {
+		val, err := strconv.Atoi(query.Get("page[size]"))
+		if err != nil || val < 1 || val > maxAuditPageSize {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "page[size] must be between 1 and " + strconv.Itoa(maxAuditPageSize),
+			})
+			return
+		}
+		parameters.PageSize = val
+	}
+
+	resp, err := a.Backend.AuditEventsList(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+// Export writes the hash chain of the organization as NDJSON, one record per
+// line in sequence order, for "tfrepoctl audit verify". Unfiltered exports end
+// with a checkpoint naming the head of the chain, which is logged as well so
+// the head is kept outside the backend.
+func (a *AuditAPI) Export(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.AuditParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	query := r.URL.Query()
+
+	var err error
+	parameters.Since, err = queryTime(query, "since")
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	parameters.Until, err = queryTime(query, "until")
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	records, err := a.Backend.AuditEventsExport(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			slog.ErrorContext(r.Context(), "Error writing audit export", "error", err)
+			return
+		}
+	}
+
+	if parameters.Since != nil || parameters.Until != nil {
+		return
+	}
+
+	checkpoint := chain.NewCheckpoint(parameters.Organization, records, []byte(a.Config.AuditSigningKey), time.Now())
+	slog.InfoContext(r.Context(), "Audit chain checkpoint", "organization", checkpoint.Organization, "sequence", checkpoint.Sequence, "hash", checkpoint.Hash)
+	if err := chain.WriteCheckpoint(w, checkpoint); err != nil {
+		slog.ErrorContext(r.Context(), "Error writing audit export", "error", err)
+	}
+}
+
+func queryTime(query url.Values, name string) (*time.Time, error) {
+	if !query.Has(name) {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, query.Get(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+	}
+
+	return &value, nil
+}
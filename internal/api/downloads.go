@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+)
+
+type DownloadsAPI api
+
+func (a *DownloadsAPI) Provider(w http.ResponseWriter, r *http.Request) {
+	organization := chi.URLParam(r, "organization")
+	registry := chi.URLParam(r, "registry")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	if !strings.EqualFold(organization, namespace) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "namespace must match organization",
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: organization,
+		Registry:     registry,
+		Namespace:    namespace,
+		Name:         name,
+	}
+
+	if _, err := a.Backend.ProvidersGet(r.Context(), parameters); err != nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	a.stats(w, r, fmt.Sprintf("%s/%s/%s", registry, namespace, name), registrytypes.DownloadParameters{
+		Kind:      registrytypes.DownloadKindProvider,
+		Namespace: namespace,
+		Name:      name,
+	})
+}
+
+func (a *DownloadsAPI) Module(w http.ResponseWriter, r *http.Request) {
+	organization := chi.URLParam(r, "organization")
+	registry := chi.URLParam(r, "registry")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	provider := chi.URLParam(r, "provider")
+
+	if !strings.EqualFold(organization, namespace) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "namespace must match organization",
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: organization,
+		Registry:     registry,
+		Namespace:    namespace,
+		Name:         name,
+		Provider:     provider,
+	}
+
+	if _, err := a.Backend.ModulesGet(r.Context(), parameters); err != nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	a.stats(w, r, fmt.Sprintf("%s/%s/%s/%s", registry, namespace, name, provider), registrytypes.DownloadParameters{
+		Kind:      registrytypes.DownloadKindModule,
+		Namespace: namespace,
+		Name:      name,
+		Provider:  provider,
+	})
+}
+
+// stats writes the counters of parameters, limited to the version given in the
+// query string if any.
+func (a *DownloadsAPI) stats(w http.ResponseWriter, r *http.Request, id string, parameters registrytypes.DownloadParameters) {
+	counters, err := a.Backend.DownloadsList(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	attributes := models.DownloadsAttributesResponse{
+		Version:           r.URL.Query().Get("version"),
+		Versions:          map[string]int64{},
+		Days:              map[string]int64{},
+		TerraformVersions: map[string]int64{},
+	}
+	if parameters.Kind == registrytypes.DownloadKindProvider {
+		attributes.Platforms = map[string]int64{}
+	}
+
+	for _, counter := range counters {
+		if attributes.Version != "" && counter.Version != attributes.Version {
+			continue
+		}
+
+		switch counter.Dimension {
+		case registrytypes.DownloadDimensionTotal:
+			attributes.Total += counter.Count
+			attributes.Versions[counter.Version] += counter.Count
+		case registrytypes.DownloadDimensionDay:
+			attributes.Days[counter.Key] += counter.Count
+		case registrytypes.DownloadDimensionPlatform:
+			if attributes.Platforms != nil {
+				attributes.Platforms[counter.Key] += counter.Count
+			}
+		case registrytypes.DownloadDimensionTerraform:
+			attributes.TerraformVersions[counter.Key] += counter.Count
+		}
+	}
+
+	response.JsonResponse(w, http.StatusOK, models.DownloadsResponse{
+		Data: models.DownloadsDataResponse{
+			ID:         id,
+			Type:       "download-stats",
+			Attributes: attributes,
+		},
+	})
+}
+
+// versionDownloads returns the total downloads per version of parameters. The
+// counts are informational, so a failing backend yields no counts rather than
+// failing the request.
+func versionDownloads(r *http.Request, b backend.DownloadsBackend, parameters registrytypes.DownloadParameters) map[string]int64 {
+	totals := map[string]int64{}
+
+	counters, err := b.DownloadsList(r.Context(), parameters)
+	if err != nil {
+		return totals
+	}
+
+	for _, counter := range counters {
+		if counter.Dimension == registrytypes.DownloadDimensionTotal {
+			totals[counter.Version] += counter.Count
+		}
+	}
+
+	return totals
+}
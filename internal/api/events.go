@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/response"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const keepAliveInterval = 15 * time.Second
+
+type EventsAPI api
+
+// Stream sends the events of the organization as server-sent events. Clients
+// reconnecting with Last-Event-ID first receive what they missed from the
+// event log.
+func (a *EventsAPI) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: "Streaming is not supported",
+		})
+		return
+	}
+
+	organization := chi.URLParam(r, "organization")
+
+	// Streams are exempt from the server read and write timeouts
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		slog.WarnContext(r.Context(), "Unable to clear read deadline of event stream", "error", err)
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		slog.WarnContext(r.Context(), "Unable to clear write deadline of event stream", "error", err)
+	}
+
+	// Subscribe before reading the log so nothing falls in between
+	stream, unsubscribe := a.Broker.Subscribe(organization)
+	defer unsubscribe()
+
+	var backlog []events.Event
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		var err error
+		backlog, err = a.Backend.EventsList(r.Context(), organization, lastEventID)
+		if err != nil {
+			response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sent := map[string]bool{}
+	for _, event := range backlog {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+		sent[event.ID] = true
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			if sent[event.ID] {
+				delete(sent, event.ID)
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
 	"go-terraform-registry/internal/response"
 	"net/http"
 	"strconv"
@@ -46,6 +48,9 @@ func (a *GPGKeysAPI) Add(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+
+	audit.Annotate(r.Context(), req.Data.Attributes.Namespace, req.Data.Attributes.Namespace)
+
 	resp, err := a.Backend.GPGKeysAdd(r.Context(), req)
 	if err != nil {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
@@ -54,6 +59,8 @@ func (a *GPGKeysAPI) Add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s", req.Data.Attributes.Namespace, resp.Data.Attributes.KeyID))
+
 	response.JsonResponse(w, http.StatusCreated, resp)
 }
 
@@ -0,0 +1,18 @@
+package models
+
+type AccountResponse struct {
+	Data AccountDataResponse `json:"data"`
+}
+
+type AccountDataResponse struct {
+	ID         string                    `json:"id"`
+	Type       string                    `json:"type"`
+	Attributes AccountAttributesResponse `json:"attributes"`
+}
+
+type AccountAttributesResponse struct {
+	Username         string   `json:"username"`
+	Email            string   `json:"email"`
+	IsServiceAccount bool     `json:"is-service-account"`
+	Organizations    []string `json:"organizations"`
+}
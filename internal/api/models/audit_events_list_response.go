@@ -0,0 +1,24 @@
+package models
+
+type AuditEventsListResponse struct {
+	Data  []AuditEventsDataResponse `json:"data"`
+	Links Links                     `json:"links"`
+	Meta  Meta                      `json:"meta"`
+}
+
+type AuditEventsDataResponse struct {
+	ID         string                        `json:"id"`
+	Type       string                        `json:"type"`
+	Attributes AuditEventsAttributesResponse `json:"attributes"`
+}
+
+type AuditEventsAttributesResponse struct {
+	Timestamp    string `json:"timestamp"`
+	Organization string `json:"organization"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	Target       string `json:"target"`
+	SourceIP     string `json:"source-ip"`
+	Outcome      string `json:"outcome"`
+	StatusCode   int    `json:"status-code"`
+}
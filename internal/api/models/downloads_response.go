@@ -0,0 +1,20 @@
+package models
+
+type DownloadsResponse struct {
+	Data DownloadsDataResponse `json:"data"`
+}
+
+type DownloadsDataResponse struct {
+	ID         string                      `json:"id"`
+	Type       string                      `json:"type"`
+	Attributes DownloadsAttributesResponse `json:"attributes"`
+}
+
+type DownloadsAttributesResponse struct {
+	Version           string           `json:"version,omitempty"`
+	Total             int64            `json:"total"`
+	Versions          map[string]int64 `json:"versions"`
+	Days              map[string]int64 `json:"days"`
+	Platforms         map[string]int64 `json:"platforms,omitempty"`
+	TerraformVersions map[string]int64 `json:"terraform-versions"`
+}
@@ -19,3 +19,27 @@ type PaginationMeta struct {
 type Meta struct {
 	Pagination PaginationMeta `json:"pagination"`
 }
+
+func NewPaginationMeta(currentPage int, pageSize int, totalCount int) PaginationMeta {
+	totalPages := (totalCount + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	meta := PaginationMeta{
+		PageSize:    pageSize,
+		CurrentPage: currentPage,
+		TotalPages:  totalPages,
+		TotalCount:  totalCount,
+	}
+	if currentPage > 1 {
+		prev := currentPage - 1
+		meta.PrevPage = &prev
+	}
+	if currentPage < totalPages {
+		next := currentPage + 1
+		meta.NextPage = &next
+	}
+
+	return meta
+}
@@ -22,6 +22,7 @@ type ModulesAttributesResponse struct {
 	CreatedAt       string                     `json:"created-at"`
 	UpdatedAt       string                     `json:"updated-at"`
 	Permissions     ModulesPermissionsResponse `json:"permissions"`
+	Downloads       int64                      `json:"downloads"`
 }
 
 type ModulesPermissionsResponse struct {
@@ -0,0 +1,16 @@
+package models
+
+type NamespacesRequest struct {
+	Data NamespacesDataRequest `json:"data"`
+}
+
+type NamespacesDataRequest struct {
+	Type       string                      `json:"type"`
+	Attributes NamespacesAttributesRequest `json:"attributes"`
+}
+
+type NamespacesAttributesRequest struct {
+	Public          *bool     `json:"public"`
+	PublicModules   *[]string `json:"public-modules"`
+	PublicProviders *[]string `json:"public-providers"`
+}
@@ -0,0 +1,19 @@
+package models
+
+type NamespacesResponse struct {
+	Data NamespacesDataResponse `json:"data"`
+}
+
+type NamespacesDataResponse struct {
+	ID         string                       `json:"id"`
+	Type       string                       `json:"type"`
+	Attributes NamespacesAttributesResponse `json:"attributes"`
+}
+
+type NamespacesAttributesResponse struct {
+	Name            string   `json:"name"`
+	Public          bool     `json:"public"`
+	PublicModules   []string `json:"public-modules"`
+	PublicProviders []string `json:"public-providers"`
+	UpdatedAt       string   `json:"updated-at"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type OrganizationsListResponse struct {
+	Data  []OrganizationsDataResponse `json:"data"`
+	Links Links                       `json:"links"`
+	Meta  Meta                        `json:"meta"`
+}
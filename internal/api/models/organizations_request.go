@@ -0,0 +1,16 @@
+package models
+
+type OrganizationsRequest struct {
+	Data OrganizationsDataRequest `json:"data"`
+}
+
+type OrganizationsDataRequest struct {
+	Type       string                         `json:"type"`
+	Attributes OrganizationsAttributesRequest `json:"attributes"`
+}
+
+type OrganizationsAttributesRequest struct {
+	Name            *string `json:"name"`
+	Email           *string `json:"email"`
+	DefaultRegistry *string `json:"default-registry"`
+}
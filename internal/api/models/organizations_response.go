@@ -0,0 +1,32 @@
+package models
+
+type OrganizationsResponse struct {
+	Data OrganizationsDataResponse `json:"data"`
+}
+
+type OrganizationsDataResponse struct {
+	ID         string                          `json:"id"`
+	Type       string                          `json:"type"`
+	Attributes OrganizationsAttributesResponse `json:"attributes"`
+	Links      OrganizationsLinksResponse      `json:"links"`
+}
+
+type OrganizationsAttributesResponse struct {
+	Name            string                           `json:"name"`
+	Email           string                           `json:"email"`
+	DefaultRegistry string                           `json:"default-registry"`
+	ExternalID      string                           `json:"external-id"`
+	CreatedAt       string                           `json:"created-at"`
+	Permissions     OrganizationsPermissionsResponse `json:"permissions"`
+}
+
+type OrganizationsPermissionsResponse struct {
+	CanCreateModule   bool `json:"can-create-module"`
+	CanCreateProvider bool `json:"can-create-provider"`
+	CanUpdate         bool `json:"can-update"`
+	CanDestroy        bool `json:"can-destroy"`
+}
+
+type OrganizationsLinksResponse struct {
+	Self string `json:"self"`
+}
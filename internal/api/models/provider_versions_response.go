@@ -21,6 +21,7 @@ type ProviderVersionsAttributesResponse struct {
 	Permissions        ProviderVersionsPermissionsResponse `json:"permissions"`
 	ShasumsUploaded    bool                                `json:"shasums-uploaded"`
 	ShasumsSigUploaded bool                                `json:"shasums-sig-uploaded"`
+	Downloads          int64                               `json:"downloads"`
 }
 
 type ProviderVersionsPermissionsResponse struct {
@@ -0,0 +1,7 @@
+package models
+
+type SharesListResponse struct {
+	Data  []SharesDataResponse `json:"data"`
+	Links Links                `json:"links"`
+	Meta  Meta                 `json:"meta"`
+}
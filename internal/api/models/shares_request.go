@@ -0,0 +1,18 @@
+package models
+
+type SharesRequest struct {
+	Data SharesDataRequest `json:"data"`
+}
+
+type SharesDataRequest struct {
+	Type       string                  `json:"type"`
+	Attributes SharesAttributesRequest `json:"attributes"`
+}
+
+type SharesAttributesRequest struct {
+	GranteeOrganization string `json:"grantee-organization"`
+	ResourceType        string `json:"resource-type"`
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name"`
+	Provider            string `json:"provider"`
+}
@@ -0,0 +1,21 @@
+package models
+
+type SharesResponse struct {
+	Data SharesDataResponse `json:"data"`
+}
+
+type SharesDataResponse struct {
+	ID         string                   `json:"id"`
+	Type       string                   `json:"type"`
+	Attributes SharesAttributesResponse `json:"attributes"`
+}
+
+type SharesAttributesResponse struct {
+	Organization        string `json:"organization"`
+	GranteeOrganization string `json:"grantee-organization"`
+	ResourceType        string `json:"resource-type"`
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name"`
+	Provider            string `json:"provider,omitempty"`
+	CreatedAt           string `json:"created-at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type WebhookDeliveriesListResponse struct {
+	Data  []WebhookDeliveriesDataResponse `json:"data"`
+	Links Links                           `json:"links"`
+	Meta  Meta                            `json:"meta"`
+}
+
+type WebhookDeliveriesDataResponse struct {
+	ID         string                              `json:"id"`
+	Type       string                              `json:"type"`
+	Attributes WebhookDeliveriesAttributesResponse `json:"attributes"`
+}
+
+type WebhookDeliveriesAttributesResponse struct {
+	EventID    string `json:"event-id"`
+	EventType  string `json:"event-type"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status-code"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration-ms"`
+	Success    bool   `json:"success"`
+	CreatedAt  string `json:"created-at"`
+}
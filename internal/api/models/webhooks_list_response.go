@@ -0,0 +1,7 @@
+package models
+
+type WebhooksListResponse struct {
+	Data  []WebhooksDataResponse `json:"data"`
+	Links Links                  `json:"links"`
+	Meta  Meta                   `json:"meta"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type WebhooksRequest struct {
+	Data WebhooksDataRequest `json:"data"`
+}
+
+type WebhooksDataRequest struct {
+	Type       string                    `json:"type"`
+	Attributes WebhooksAttributesRequest `json:"attributes"`
+}
+
+type WebhooksAttributesRequest struct {
+	URL     *string   `json:"url"`
+	Events  *[]string `json:"events"`
+	Secret  *string   `json:"secret"`
+	Enabled *bool     `json:"enabled"`
+}
@@ -0,0 +1,20 @@
+package models
+
+type WebhooksResponse struct {
+	Data WebhooksDataResponse `json:"data"`
+}
+
+type WebhooksDataResponse struct {
+	ID         string                     `json:"id"`
+	Type       string                     `json:"type"`
+	Attributes WebhooksAttributesResponse `json:"attributes"`
+}
+
+type WebhooksAttributesResponse struct {
+	Organization string   `json:"organization"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`
+	Enabled      bool     `json:"enabled"`
+	CreatedAt    string   `json:"created-at"`
+	UpdatedAt    string   `json:"updated-at"`
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/events"
 	"go-terraform-registry/internal/response"
 	registrytypes "go-terraform-registry/internal/types"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 )
@@ -31,6 +33,8 @@ func (a *ModuleVersionsAPI) Create(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	provider := chi.URLParam(r, "provider")
 
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s/%s/%s/%s", registry, namespace, name, provider, req.Data.Attributes.Version))
+
 	if !strings.EqualFold(organization, namespace) {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: "namespace must match organization",
@@ -54,6 +58,8 @@ func (a *ModuleVersionsAPI) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	a.Events.Publish(events.NewEvent(events.ModuleVersionCreated, organization, fmt.Sprintf("%s/%s/%s/%s", namespace, name, provider, req.Data.Attributes.Version), nil))
+
 	key := fmt.Sprintf("%s/%s/%s/%s/%s/%s/%s", "modules", parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name, parameters.Provider, req.Data.Attributes.Version)
 	file := fmt.Sprintf("terraform-%s-%s-%s.tar.gz", parameters.Provider, parameters.Name, req.Data.Attributes.Version)
 	moduleURL, err := a.Storage.GenerateUploadURL(r.Context(), fmt.Sprintf("%s/%s", key, file))
@@ -90,11 +96,13 @@ func (a *ModuleVersionsAPI) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	a.Events.Publish(events.NewEvent(events.ModuleVersionDeleted, organization, fmt.Sprintf("%s/%s/%s/%s", namespace, name, provider, version), nil))
+
 	key := fmt.Sprintf("%s/%s/%s/%s/%s/%s/%s", "modules", parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name, parameters.Provider, parameters.Version)
 	file := fmt.Sprintf("terraform-%s-%s-%s.tar.gz", parameters.Provider, parameters.Name, parameters.Version)
 	err = a.Storage.RemoveFile(r.Context(), fmt.Sprintf("%s/%s", key, file))
 	if err != nil {
-		log.Printf("Error removing file: %s", err.Error())
+		slog.ErrorContext(r.Context(), "Error removing file", "error", err)
 	}
 
 	w.WriteHeader(statusCode)
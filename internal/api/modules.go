@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
 	"go-terraform-registry/internal/response"
 	registrytypes "go-terraform-registry/internal/types"
 	"net/http"
@@ -36,6 +38,15 @@ func (a *ModulesAPI) Create(w http.ResponseWriter, r *http.Request) {
 		Organization: organization,
 	}
 
+	if req.Data.Attributes.RegistryName == "" {
+		org, err := a.Backend.OrganizationsGet(r.Context(), parameters)
+		if err == nil && org != nil {
+			req.Data.Attributes.RegistryName = org.Data.Attributes.DefaultRegistry
+		}
+	}
+
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s/%s/%s", req.Data.Attributes.RegistryName, req.Data.Attributes.Namespace, req.Data.Attributes.Name, req.Data.Attributes.Provider))
+
 	resp, err := a.Backend.ModulesCreate(r.Context(), parameters, req)
 	if err != nil {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
@@ -77,5 +88,17 @@ func (a *ModulesAPI) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	downloads := versionDownloads(r, a.Backend, registrytypes.DownloadParameters{
+		Kind:      registrytypes.DownloadKindModule,
+		Namespace: namespace,
+		Name:      name,
+		Provider:  provider,
+	})
+	if resp != nil {
+		for _, count := range downloads {
+			resp.Data.Attributes.Downloads += count
+		}
+	}
+
 	response.JsonResponse(w, http.StatusOK, resp)
 }
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+)
+
+type NamespacesAPI api
+
+func (a *NamespacesAPI) Get(w http.ResponseWriter, r *http.Request) {
+	organization := chi.URLParam(r, "organization")
+	namespace := chi.URLParam(r, "namespace")
+
+	if !strings.EqualFold(organization, namespace) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "namespace must match organization",
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: organization,
+		Namespace:    namespace,
+	}
+
+	resp, err := a.Backend.NamespacesGet(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *NamespacesAPI) Update(w http.ResponseWriter, r *http.Request) {
+	var req models.NamespacesRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	organization := chi.URLParam(r, "organization")
+	namespace := chi.URLParam(r, "namespace")
+
+	if !strings.EqualFold(organization, namespace) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "namespace must match organization",
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: organization,
+		Namespace:    namespace,
+	}
+
+	resp, err := a.Backend.NamespacesUpdate(r.Context(), parameters, req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
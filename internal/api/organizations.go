@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/auth"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var organizationNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+type OrganizationsAPI api
+
+func (a *OrganizationsAPI) List(w http.ResponseWriter, r *http.Request) {
+	organizations, err := a.Backend.OrganizationsList(r.Context())
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	resp := &models.OrganizationsListResponse{
+		Data: []models.OrganizationsDataResponse{},
+	}
+
+	for _, organization := range organizations.Data {
+		if !tokenOrganization(r, organization.ID) {
+			continue
+		}
+
+		setOrganizationPermissions(r, &organization)
+		resp.Data = append(resp.Data, organization)
+	}
+
+	resp.Meta = models.Meta{
+		Pagination: models.PaginationMeta{
+			PageSize:    len(resp.Data),
+			CurrentPage: 1,
+			TotalPages:  1,
+			TotalCount:  len(resp.Data),
+		},
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *OrganizationsAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.OrganizationsRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if req.Data.Attributes.Name == nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "name is required",
+		})
+		return
+	}
+
+	audit.Annotate(r.Context(), *req.Data.Attributes.Name, *req.Data.Attributes.Name)
+
+	if !a.validate(w, r, req.Data.Attributes) {
+		return
+	}
+
+	resp, err := a.Backend.OrganizationsCreate(r.Context(), req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	setOrganizationPermissions(r, &resp.Data)
+	response.JsonResponse(w, http.StatusCreated, resp)
+}
+
+func (a *OrganizationsAPI) Get(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.OrganizationsGet(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if resp == nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: "organization not found",
+		})
+		return
+	}
+
+	setOrganizationPermissions(r, &resp.Data)
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *OrganizationsAPI) Update(w http.ResponseWriter, r *http.Request) {
+	var req models.OrganizationsRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if !a.validate(w, r, req.Data.Attributes) {
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.OrganizationsUpdate(r.Context(), parameters, req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	setOrganizationPermissions(r, &resp.Data)
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *OrganizationsAPI) Delete(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	statusCode, err := a.Backend.OrganizationsDelete(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, statusCode, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	for _, prefix := range []string{"providers", "modules"} {
+		err = a.Storage.RemoveDirectory(r.Context(), fmt.Sprintf("%s/%s", prefix, parameters.Organization))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Error removing directory", "error", err)
+		}
+	}
+
+	w.WriteHeader(statusCode)
+}
+
+// validate checks the attributes shared by create and update. New names must
+// be covered by the token, otherwise the caller would lock itself out.
+func (a *OrganizationsAPI) validate(w http.ResponseWriter, r *http.Request, attributes models.OrganizationsAttributesRequest) bool {
+	if attributes.Name != nil {
+		if !organizationNamePattern.MatchString(*attributes.Name) {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "name may only contain letters, numbers, - and _",
+			})
+			return false
+		}
+
+		if !tokenOrganization(r, *attributes.Name) {
+			response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+				Error: "Invalid token for organization",
+			})
+			return false
+		}
+	}
+
+	if attributes.DefaultRegistry != nil && *attributes.DefaultRegistry != "private" && *attributes.DefaultRegistry != "public" {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "default-registry must be private or public",
+		})
+		return false
+	}
+
+	return true
+}
+
+func tokenOrganization(r *http.Request, name string) bool {
+	organizations, _ := r.Context().Value("organization").([]string)
+	for _, organization := range organizations {
+		if strings.EqualFold(organization, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setOrganizationPermissions(r *http.Request, organization *models.OrganizationsDataResponse) {
+	canWrite := r.Context().Value("role") != auth.RoleRead
+	organization.Attributes.Permissions = models.OrganizationsPermissionsResponse{
+		CanCreateModule:   canWrite,
+		CanCreateProvider: canWrite,
+		CanUpdate:         canWrite,
+		CanDestroy:        canWrite,
+	}
+	organization.Links.Self = fmt.Sprintf("/api/v2/organizations/%s", organization.ID)
+}
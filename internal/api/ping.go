@@ -0,0 +1,11 @@
+package api
+
+import (
+	"net/http"
+)
+
+// Ping is called by go-tfe based clients to discover the API version.
+func Ping(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("TFP-API-Version", "2.5")
+	w.WriteHeader(http.StatusNoContent)
+}
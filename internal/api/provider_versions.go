@@ -2,12 +2,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/events"
 	"go-terraform-registry/internal/response"
 	registrytypes "go-terraform-registry/internal/types"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 )
@@ -30,6 +34,8 @@ func (a *ProviderVersionsAPI) CreateVersion(w http.ResponseWriter, r *http.Reque
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
 
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s/%s/%s", registry, namespace, name, req.Data.Attributes.Version))
+
 	if registry != "private" {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: "registry must be private",
@@ -51,7 +57,15 @@ func (a *ProviderVersionsAPI) CreateVersion(w http.ResponseWriter, r *http.Reque
 		Name:         name,
 	}
 
+	subject := fmt.Sprintf("%s/%s/%s", namespace, name, req.Data.Attributes.Version)
+
 	resp, err := a.Backend.ProviderVersionsCreate(r.Context(), parameters, req)
+	if errors.Is(err, backend.ErrSigningKeyNotFound) {
+		a.Events.Publish(events.NewEvent(events.ProviderVersionVerificationFailed, organization, subject, map[string]string{
+			"key-id": req.Data.Attributes.KeyID,
+			"reason": "signing key is not registered for the namespace",
+		}))
+	}
 	if err != nil {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: err.Error(),
@@ -59,6 +73,10 @@ func (a *ProviderVersionsAPI) CreateVersion(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	a.Events.Publish(events.NewEvent(events.ProviderVersionCreated, organization, subject, map[string]string{
+		"key-id": req.Data.Attributes.KeyID,
+	}))
+
 	shaSum := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", parameters.Name, req.Data.Attributes.Version)
 	shaSumSig := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS.sig", parameters.Name, req.Data.Attributes.Version)
 
@@ -110,6 +128,12 @@ func (a *ProviderVersionsAPI) ListVersions(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	downloads := versionDownloads(r, a.Backend, registrytypes.DownloadParameters{
+		Kind:      registrytypes.DownloadKindProvider,
+		Namespace: namespace,
+		Name:      name,
+	})
+
 	for i, d := range resp.Data {
 		// Create related links
 		related := fmt.Sprintf("/api/v2/organizations/%s/registry-providers/%s/%s/%s/versions/%s/platforms", parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name, d.Attributes.Version)
@@ -119,6 +143,8 @@ func (a *ProviderVersionsAPI) ListVersions(w http.ResponseWriter, r *http.Reques
 		resp.Data[i].Attributes.Permissions.CanDelete = true
 		resp.Data[i].Attributes.Permissions.CanUploadAsset = true
 
+		resp.Data[i].Attributes.Downloads = downloads[d.Attributes.Version]
+
 		// Set the download URL's
 		shaSum := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", parameters.Name, d.Attributes.Version)
 		shaSumSig := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS.sig", parameters.Name, d.Attributes.Version)
@@ -176,6 +202,12 @@ func (a *ProviderVersionsAPI) GetVersion(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	resp.Data.Attributes.Downloads = versionDownloads(r, a.Backend, registrytypes.DownloadParameters{
+		Kind:      registrytypes.DownloadKindProvider,
+		Namespace: namespace,
+		Name:      name,
+	})[version]
+
 	response.JsonResponse(w, http.StatusOK, resp)
 }
 
@@ -216,10 +248,12 @@ func (a *ProviderVersionsAPI) DeleteVersion(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	a.Events.Publish(events.NewEvent(events.ProviderVersionDeleted, organization, fmt.Sprintf("%s/%s/%s", namespace, name, version), nil))
+
 	key := fmt.Sprintf("%s/%s/%s/%s/%s/%s", "providers", parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name, parameters.Version)
 	err = a.Storage.RemoveDirectory(r.Context(), key)
 	if err != nil {
-		log.Printf("Error removing directory: %s", err.Error())
+		slog.ErrorContext(r.Context(), "Error removing directory", "error", err)
 	}
 
 	w.WriteHeader(statusCode)
@@ -242,6 +276,8 @@ func (a *ProviderVersionsAPI) CreatePlatform(w http.ResponseWriter, r *http.Requ
 	name := chi.URLParam(r, "name")
 	version := chi.URLParam(r, "version")
 
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s/%s/%s/%s/%s", registry, namespace, name, version, req.Data.Attributes.OS, req.Data.Attributes.Arch))
+
 	if registry != "private" {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
 			Error: "registry must be private",
@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
 	"go-terraform-registry/internal/response"
 	registrytypes "go-terraform-registry/internal/types"
 	"net/http"
@@ -42,6 +44,15 @@ func (a *ProvidersAPI) Create(w http.ResponseWriter, r *http.Request) {
 		Organization: organization,
 	}
 
+	if req.Data.Attributes.RegistryName == "" {
+		org, err := a.Backend.OrganizationsGet(r.Context(), parameters)
+		if err == nil && org != nil {
+			req.Data.Attributes.RegistryName = org.Data.Attributes.DefaultRegistry
+		}
+	}
+
+	audit.Annotate(r.Context(), "", fmt.Sprintf("%s/%s/%s", req.Data.Attributes.RegistryName, req.Data.Attributes.Namespace, req.Data.Attributes.Name))
+
 	resp, err := a.Backend.ProvidersCreate(r.Context(), parameters, req)
 	if err != nil {
 		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+)
+
+type SharesAPI api
+
+func (a *SharesAPI) List(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.SharesList(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *SharesAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.SharesRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	organization := chi.URLParam(r, "organization")
+	attributes := req.Data.Attributes
+
+	if !strings.EqualFold(organization, attributes.Namespace) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "namespace must match organization",
+		})
+		return
+	}
+
+	if attributes.GranteeOrganization == "" || strings.EqualFold(organization, attributes.GranteeOrganization) {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "grantee-organization must be another organization",
+		})
+		return
+	}
+
+	if attributes.Name == "" {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "name is required",
+		})
+		return
+	}
+
+	switch attributes.ResourceType {
+	case "registry-providers":
+		if attributes.Provider != "" {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "provider is only valid for registry-modules",
+			})
+			return
+		}
+	case "registry-modules":
+		if attributes.Provider == "" {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "provider is required for registry-modules",
+			})
+			return
+		}
+	default:
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "resource-type must be registry-providers or registry-modules",
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: organization,
+	}
+
+	resp, err := a.Backend.SharesCreate(r.Context(), parameters, req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	audit.Annotate(r.Context(), "", resp.Data.ID)
+
+	response.JsonResponse(w, http.StatusCreated, resp)
+}
+
+func (a *SharesAPI) Delete(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	statusCode, err := a.Backend.SharesDelete(r.Context(), parameters, chi.URLParam(r, "share"))
+	if err != nil {
+		response.JsonResponse(w, statusCode, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(statusCode)
+}
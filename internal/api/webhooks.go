@@ -0,0 +1,285 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"go-terraform-registry/internal/webhooks"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultWebhookDeliveriesPageSize = 20
+	maxWebhookDeliveriesPageSize     = 100
+)
+
+type WebhooksAPI api
+
+func (a *WebhooksAPI) List(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.WebhooksList(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *WebhooksAPI) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.WebhooksRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	attributes := req.Data.Attributes
+	if attributes.URL == nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "url is required",
+		})
+		return
+	}
+
+	if attributes.Secret == nil || *attributes.Secret == "" {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "secret is required",
+		})
+		return
+	}
+
+	if err := validateWebhook(attributes); err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.WebhooksCreate(r.Context(), parameters, req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	audit.Annotate(r.Context(), "", resp.Data.ID)
+
+	response.JsonResponse(w, http.StatusCreated, resp)
+}
+
+func (a *WebhooksAPI) Get(w http.ResponseWriter, r *http.Request) {
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.WebhooksGet(r.Context(), parameters, chi.URLParam(r, "webhook"))
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if resp == nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: "Webhook not found",
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *WebhooksAPI) Update(w http.ResponseWriter, r *http.Request) {
+	var req models.WebhooksRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	webhookID := chi.URLParam(r, "webhook")
+	audit.Annotate(r.Context(), "", webhookID)
+
+	attributes := req.Data.Attributes
+	if attributes.Secret != nil && *attributes.Secret == "" {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: "secret must not be empty",
+		})
+		return
+	}
+
+	if err := validateWebhook(attributes); err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	resp, err := a.Backend.WebhooksUpdate(r.Context(), parameters, webhookID, req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if resp == nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: "Webhook not found",
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func (a *WebhooksAPI) Delete(w http.ResponseWriter, r *http.Request) {
+	webhookID := chi.URLParam(r, "webhook")
+	audit.Annotate(r.Context(), "", webhookID)
+
+	parameters := registrytypes.APIParameters{
+		Organization: chi.URLParam(r, "organization"),
+	}
+
+	statusCode, err := a.Backend.WebhooksDelete(r.Context(), parameters, webhookID)
+	if err != nil {
+		response.JsonResponse(w, statusCode, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(statusCode)
+}
+
+// Deliveries lists the delivery log of a webhook, newest attempt first.
+func (a *WebhooksAPI) Deliveries(w http.ResponseWriter, r *http.Request) {
+	organization := chi.URLParam(r, "organization")
+	webhookID := chi.URLParam(r, "webhook")
+
+	// Delivery logs are keyed by webhook only, check it belongs to the organization
+	webhook, err := a.Backend.WebhooksGet(r.Context(), registrytypes.APIParameters{Organization: organization}, webhookID)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if webhook == nil {
+		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+			Error: "Webhook not found",
+		})
+		return
+	}
+
+	parameters := registrytypes.WebhookDeliveryParameters{
+		Organization: organization,
+		WebhookID:    webhookID,
+		PageNumber:   1,
+		PageSize:     defaultWebhookDeliveriesPageSize,
+	}
+
+	query := r.URL.Query()
+
+	if query.Has("page[number]") {
+		val, err := strconv.Atoi(query.Get("page[number]"))
+		if err != nil || val < 1 {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "page[number] must be a positive number",
+			})
+			return
+		}
+		parameters.PageNumber = val
+	}
+
+	if query.Has("page[size]") {
+		val, err := strconv.Atoi(query.Get("page[size]"))
+		if err != nil || val < 1 || val > maxWebhookDeliveriesPageSize {
+			response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+				Error: "page[size] must be between 1 and " + strconv.Itoa(maxWebhookDeliveriesPageSize),
+			})
+			return
+		}
+		parameters.PageSize = val
+	}
+
+	resp, err := a.Backend.WebhookDeliveriesList(r.Context(), parameters)
+	if err != nil {
+		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	response.JsonResponse(w, http.StatusOK, resp)
+}
+
+func validateWebhook(attributes models.WebhooksAttributesRequest) error {
+	if attributes.URL != nil {
+		u, err := url.Parse(*attributes.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an absolute http or https URL")
+		}
+		// Hostnames are checked again on every delivery once resolved
+		if strings.EqualFold(u.Hostname(), "localhost") {
+			return fmt.Errorf("url must not point to a loopback, private or link-local address")
+		}
+		if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !webhooks.AllowedAddress(addr) {
+			return fmt.Errorf("url must not point to a loopback, private or link-local address")
+		}
+	}
+
+	if attributes.Events != nil {
+		for _, eventType := range *attributes.Events {
+			if eventType == "*" || events.ValidType(eventType) {
+				continue
+			}
+			if prefix, ok := strings.CutSuffix(eventType, "*"); ok && prefix != "" && validEventPrefix(prefix) {
+				continue
+			}
+
+			return fmt.Errorf("unknown event type %q, must be one of %s", eventType, strings.Join(events.Types, ", "))
+		}
+	}
+
+	return nil
+}
+
+func validEventPrefix(prefix string) bool {
+	for _, eventType := range events.Types {
+		if strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
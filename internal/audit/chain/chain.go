@@ -0,0 +1,235 @@
+package chain
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is an audit event as it appears in the NDJSON export. Hash covers
+// every other field, PrevHash links the record to the one before it.
+type Record struct {
+	Sequence     int64  `json:"sequence"`
+	ID           string `json:"id"`
+	Timestamp    string `json:"timestamp"`
+	Organization string `json:"organization"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	Target       string `json:"target"`
+	SourceIP     string `json:"source_ip"`
+	Outcome      string `json:"outcome"`
+	StatusCode   int    `json:"status_code"`
+	PrevHash     string `json:"prev_hash"`
+	Hash         string `json:"hash"`
+}
+
+// Checkpoint is the last line of an unfiltered export. It names the head of
+// the chain when the export was taken and is signed with the audit signing
+// key, so an export cannot be truncated or rehashed without the key. Kept
+// elsewhere, a checkpoint also shows later exports still contain that head.
+type Checkpoint struct {
+	Organization string `json:"organization"`
+	Sequence     int64  `json:"sequence"`
+	Hash         string `json:"hash"`
+	Timestamp    string `json:"timestamp"`
+	Signature    string `json:"signature"`
+}
+
+// Head is the sequence and hash of a record known from outside the export,
+// such as an earlier checkpoint.
+type Head struct {
+	Sequence int64
+	Hash     string
+}
+
+// ParseHead parses a head given as sequence:hash.
+func ParseHead(value string) (Head, error) {
+	sequence, hash, ok := strings.Cut(value, ":")
+	if !ok || hash == "" {
+		return Head{}, fmt.Errorf("head must be sequence:hash")
+	}
+
+	number, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil || number < 1 {
+		return Head{}, fmt.Errorf("head sequence must be a positive number")
+	}
+
+	return Head{Sequence: number, Hash: hash}, nil
+}
+
+// VerifyOptions control the checks beyond the links between records.
+type VerifyOptions struct {
+	// Key verifies the checkpoint signature
+	Key []byte
+	// Partial accepts an export filtered by time, which starts after the
+	// first record and has no checkpoint
+	Partial bool
+	// Head must be contained in the export
+	Head *Head
+}
+
+type checkpointLine struct {
+	Checkpoint *Checkpoint `json:"checkpoint"`
+}
+
+type Problem struct {
+	Line     int
+	Sequence int64
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d, sequence %d: %s", p.Line, p.Sequence, p.Message)
+}
+
+// ComputeHash returns the SHA-256 of the record's JSON encoding with Hash
+// left empty. Struct fields marshal in declaration order, so the encoding is
+// stable.
+func (r Record) ComputeHash() string {
+	r.Hash = ""
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeSignature returns the hex encoded HMAC-SHA256 of the checkpoint's
+// JSON encoding with Signature left empty.
+func (c Checkpoint) ComputeSignature(key []byte) string {
+	c.Signature = ""
+	data, _ := json.Marshal(c)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewCheckpoint returns the checkpoint for the records of an unfiltered
+// export, signed when a key is given.
+func NewCheckpoint(organization string, records []Record, key []byte, timestamp time.Time) Checkpoint {
+	checkpoint := Checkpoint{
+		Organization: organization,
+		Timestamp:    timestamp.UTC().Format(time.RFC3339Nano),
+	}
+	if len(records) > 0 {
+		checkpoint.Sequence = records[len(records)-1].Sequence
+		checkpoint.Hash = records[len(records)-1].Hash
+	}
+	if len(key) > 0 {
+		checkpoint.Signature = checkpoint.ComputeSignature(key)
+	}
+
+	return checkpoint
+}
+
+// WriteCheckpoint writes the checkpoint line of an export.
+func WriteCheckpoint(writer io.Writer, checkpoint Checkpoint) error {
+	return json.NewEncoder(writer).Encode(checkpointLine{Checkpoint: &checkpoint})
+}
+
+// Link makes record the successor of the record with headSequence and
+// headHash, both zero for the first record of an organization, and sets its
+// hash.
+func Link(record *Record, headSequence int64, headHash string) {
+	record.Sequence = headSequence + 1
+	record.PrevHash = headHash
+	record.Hash = record.ComputeHash()
+}
+
+// Verify walks an NDJSON export and reports records that were modified,
+// removed or reordered. Unless the export is partial it must start at the
+// first record and end with a checkpoint naming its last record. It returns
+// the number of records read.
+func Verify(reader io.Reader, options VerifyOptions) (int, []Problem, error) {
+	var problems []Problem
+	var prev *Record
+	var checkpoint *Checkpoint
+	checkpointLineNumber := 0
+	headFound := false
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	count := 0
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		if checkpoint != nil {
+			problems = append(problems, Problem{Line: line, Message: "record after the checkpoint"})
+		}
+
+		var envelope checkpointLine
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err == nil && envelope.Checkpoint != nil {
+			checkpoint = envelope.Checkpoint
+			checkpointLineNumber = line
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			problems = append(problems, Problem{Line: line, Message: fmt.Sprintf("invalid record: %v", err)})
+			prev = nil
+			continue
+		}
+		count++
+
+		if record.ComputeHash() != record.Hash {
+			problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: "hash does not match record contents"})
+		}
+
+		if options.Head != nil && record.Sequence == options.Head.Sequence {
+			headFound = true
+			if record.Hash != options.Head.Hash {
+				problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: "hash does not match the known head"})
+			}
+		}
+
+		switch {
+		case prev == nil && count == 1 && record.Sequence != 1 && !options.Partial:
+			problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: "export does not start at sequence 1"})
+		case prev == nil:
+			if record.Sequence == 1 && record.PrevHash != "" {
+				problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: "first record has a previous hash"})
+			}
+		case record.Sequence != prev.Sequence+1:
+			problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: fmt.Sprintf("gap after sequence %d", prev.Sequence)})
+		case record.PrevHash != prev.Hash:
+			problems = append(problems, Problem{Line: line, Sequence: record.Sequence, Message: "previous hash does not match the preceding record"})
+		}
+
+		prev = &record
+	}
+	if err := scanner.Err(); err != nil {
+		return count, problems, err
+	}
+
+	var last Record
+	if prev != nil {
+		last = *prev
+	}
+
+	if options.Head != nil && !headFound {
+		problems = append(problems, Problem{Line: line, Sequence: options.Head.Sequence, Message: fmt.Sprintf("export ends at sequence %d without the known head", last.Sequence)})
+	}
+
+	switch {
+	case checkpoint == nil && !options.Partial:
+		problems = append(problems, Problem{Line: line, Sequence: last.Sequence, Message: "export has no checkpoint, records may be missing at the end"})
+	case checkpoint == nil:
+	case checkpoint.Sequence != last.Sequence || checkpoint.Hash != last.Hash:
+		problems = append(problems, Problem{Line: checkpointLineNumber, Sequence: checkpoint.Sequence, Message: fmt.Sprintf("checkpoint does not match the last record, sequence %d", last.Sequence)})
+	case len(options.Key) > 0 && !hmac.Equal([]byte(checkpoint.ComputeSignature(options.Key)), []byte(checkpoint.Signature)):
+		problems = append(problems, Problem{Line: checkpointLineNumber, Sequence: checkpoint.Sequence, Message: "checkpoint signature is invalid"})
+	}
+
+	return count, problems, nil
+}
@@ -0,0 +1,112 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("audit-signing-key")
+
+func testRecords(n int) []Record {
+	var records []Record
+	var sequence int64
+	var hash string
+	for i := 0; i < n; i++ {
+		record := Record{ID: string(rune('a' + i)), Organization: "acme", Action: "provider.create", Outcome: "success"}
+		Link(&record, sequence, hash)
+		sequence, hash = record.Sequence, record.Hash
+		records = append(records, record)
+	}
+	return records
+}
+
+func export(t *testing.T, records []Record, checkpoint *Checkpoint) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		if err := json.NewEncoder(&buf).Encode(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if checkpoint != nil {
+		if err := WriteCheckpoint(&buf, *checkpoint); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.String()
+}
+
+func signed(records []Record, key []byte) *Checkpoint {
+	checkpoint := NewCheckpoint("acme", records, key, time.Now())
+	return &checkpoint
+}
+
+func TestVerify(t *testing.T) {
+	records := testRecords(5)
+
+	rewritten := testRecords(5)[:4]
+	rewritten[1].Action = "provider.delete"
+	for i := 1; i < len(rewritten); i++ {
+		Link(&rewritten[i], rewritten[i-1].Sequence, rewritten[i-1].Hash)
+	}
+
+	tests := []struct {
+		name    string
+		export  string
+		options VerifyOptions
+		want    string
+	}{
+		{"intact", export(t, records, signed(records, testKey)), VerifyOptions{Key: testKey}, ""},
+		{"intact with known head", export(t, records, signed(records, testKey)), VerifyOptions{Key: testKey, Head: &Head{Sequence: 3, Hash: records[2].Hash}}, ""},
+		{"empty chain", export(t, nil, signed(nil, testKey)), VerifyOptions{Key: testKey}, ""},
+		{"partial", export(t, records[2:], nil), VerifyOptions{Partial: true}, ""},
+		{"first records dropped", export(t, records[2:], signed(records[2:], testKey)), VerifyOptions{Key: testKey}, "does not start at sequence 1"},
+		{"last records dropped", export(t, records[:3], signed(records, testKey)), VerifyOptions{Key: testKey}, "checkpoint does not match"},
+		{"checkpoint removed", export(t, records[:3], nil), VerifyOptions{Key: testKey}, "no checkpoint"},
+		{"checkpoint forged", export(t, rewritten, signed(rewritten, []byte("guess"))), VerifyOptions{Key: testKey}, "signature is invalid"},
+		{"checkpoint unsigned", export(t, rewritten, signed(rewritten, nil)), VerifyOptions{Key: testKey}, "signature is invalid"},
+		{"rewritten since known head", export(t, rewritten, signed(rewritten, testKey)), VerifyOptions{Key: testKey, Head: &Head{Sequence: 5, Hash: records[4].Hash}}, "without the known head"},
+		{"rehashed known head", export(t, rewritten, signed(rewritten, testKey)), VerifyOptions{Key: testKey, Head: &Head{Sequence: 2, Hash: records[1].Hash}}, "does not match the known head"},
+		{"record modified", strings.Replace(export(t, records, signed(records, testKey)), "provider.create", "provider.delete", 1), VerifyOptions{Key: testKey}, "hash does not match record contents"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, problems, err := Verify(strings.NewReader(tt.export), tt.options)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Errorf("Verify() of %d records reported %v", count, problems)
+				}
+				return
+			}
+
+			for _, problem := range problems {
+				if strings.Contains(problem.Message, tt.want) {
+					return
+				}
+			}
+			t.Errorf("Verify() problems = %v, want %q", problems, tt.want)
+		})
+	}
+}
+
+func TestParseHead(t *testing.T) {
+	head, err := ParseHead("42:abc")
+	if err != nil || head.Sequence != 42 || head.Hash != "abc" {
+		t.Errorf("ParseHead() = %v, %v", head, err)
+	}
+
+	for _, value := range []string{"", "42", "x:abc", "0:abc", "42:"} {
+		if _, err := ParseHead(value); err == nil {
+			t.Errorf("ParseHead(%q) succeeded", value)
+		}
+	}
+}
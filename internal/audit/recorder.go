@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeDenied  = "denied"
+	OutcomeFailure = "failure"
+)
+
+// targetParams are the URL parameters joined into the default target, in the
+// order they appear in registry addresses.
+var targetParams = []string{"registry", "namespace", "name", "provider", "version", "os", "arch", "key_id", "share"}
+
+type Recorder struct {
+	Backend backend.AuditBackend
+}
+
+type entry struct {
+	organization string
+	target       string
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func NewRecorder(b backend.AuditBackend) *Recorder {
+	return &Recorder{
+		Backend: b,
+	}
+}
+
+// Middleware records an audit event for action once the handler returns.
+// Handlers can refine the organization and target with Annotate when they are
+// only known from the request body.
+func (rec *Recorder) Middleware(action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			e := &entry{
+				organization: chi.URLParam(r, "organization"),
+			}
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), "audit", e)))
+
+			if e.organization == "" {
+				e.organization = chi.URLParam(r, "namespace")
+			}
+			if e.target == "" {
+				e.target = defaultTarget(r)
+			}
+
+			// Postgres keeps microseconds, the hash chain needs timestamps that
+			// read back unchanged
+			event := registrytypes.AuditEvent{
+				Organization: e.organization,
+				Actor:        actor(r.Context()),
+				Action:       action,
+				Target:       e.target,
+				SourceIP:     sourceIP(r),
+				Outcome:      outcome(sw.status),
+				StatusCode:   sw.status,
+				Timestamp:    time.Now().UTC().Truncate(time.Microsecond),
+			}
+
+			if event.Organization == "" {
+				slog.WarnContext(r.Context(), "Audit event has no organization, dropping", "action", action)
+				return
+			}
+
+			// The client may already be gone, the event must still be stored
+			err := rec.Backend.AuditEventsCreate(context.WithoutCancel(r.Context()), event)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "Error recording audit event", "action", action, "error", err)
+			}
+		})
+	}
+}
+
+// Annotate sets the organization and target of the audit event for the
+// request. Empty values leave the defaults in place.
+func Annotate(ctx context.Context, organization string, target string) {
+	e, ok := ctx.Value("audit").(*entry)
+	if !ok {
+		return
+	}
+
+	if organization != "" {
+		e.organization = organization
+	}
+	if target != "" {
+		e.target = target
+	}
+}
+
+func defaultTarget(r *http.Request) string {
+	var parts []string
+	for _, param := range targetParams {
+		if value := chi.URLParam(r, param); value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	if len(parts) == 0 {
+		return chi.URLParam(r, "organization")
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func actor(ctx context.Context) string {
+	if login, ok := ctx.Value("login").(string); ok && login != "" {
+		return login
+	}
+
+	return "anonymous"
+}
+
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func outcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= 400:
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
@@ -11,7 +11,7 @@ type RegistryClaims struct {
 	jwt.MapClaims
 }
 
-func CreateJWTToken(username string, key []byte) (*string, error) {
+func CreateJWTToken(username string, keys *TokenKeys) (*string, error) {
 	claims := jwt.MapClaims{
 		"sub":   "terraform-cli",
 		"login": username,
@@ -19,8 +19,7 @@ func CreateJWTToken(username string, key []byte) (*string, error) {
 		"exp":   time.Now().Add(time.Hour * 24 * 356).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(key)
+	signedToken, err := keys.Sign(claims)
 	if err != nil {
 		return nil, fmt.Errorf("error signing the token: %s", err)
 	}
@@ -28,7 +27,7 @@ func CreateJWTToken(username string, key []byte) (*string, error) {
 	return &signedToken, nil
 }
 
-func CreateJWTClaimsToken(username string, organization []string, key []byte) (*string, error) {
+func CreateJWTClaimsToken(username string, organization []string, keys *TokenKeys) (*string, error) {
 	claims := RegistryClaims{
 		Organization: organization,
 		MapClaims: jwt.MapClaims{
@@ -39,8 +38,7 @@ func CreateJWTClaimsToken(username string, organization []string, key []byte) (*
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(key)
+	signedToken, err := keys.Sign(claims)
 	if err != nil {
 		return nil, fmt.Errorf("error signing the token: %s", err)
 	}
@@ -48,14 +46,8 @@ func CreateJWTClaimsToken(username string, organization []string, key []byte) (*
 	return &signedToken, nil
 }
 
-func GetJWTToken(signedToken string, key []byte) (*jwt.Token, error) {
-	token, err := jwt.Parse(signedToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return key, nil
-	})
-
+func GetJWTToken(signedToken string, keys *TokenKeys) (*jwt.Token, error) {
+	token, err := jwt.Parse(signedToken, keys.Keyfunc)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing token: %s", err)
 	}
@@ -63,13 +55,8 @@ func GetJWTToken(signedToken string, key []byte) (*jwt.Token, error) {
 	return token, nil
 }
 
-func GetJWTClaimsToken(signedToken string, key []byte) (*jwt.Token, error) {
-	token, err := jwt.ParseWithClaims(signedToken, &RegistryClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return key, nil
-	})
+func GetJWTClaimsToken(signedToken string, keys *TokenKeys) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(signedToken, &RegistryClaims{}, keys.Keyfunc)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing token: %s", err)
 	}
@@ -1,23 +1,48 @@
 package auth
 
 import (
+	"context"
 	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/logging"
 	"go-terraform-registry/internal/response"
 	"net/http"
 	"strings"
 )
 
 type Authentication struct {
-	Config registryconfig.RegistryConfig
+	Config       registryconfig.RegistryConfig
+	Keys         *registryconfig.Live[*TokenKeys]
+	StaticTokens *registryconfig.Live[*StaticTokenStore]
+	Certificates *ClientCertificateMapper
 }
 
 type AuthenticationMiddleware interface {
 	AuthenticationHandlerMiddleware(http.Handler) http.Handler
 }
 
-func NewAuthenticationMiddleware(config registryconfig.RegistryConfig) AuthenticationMiddleware {
+func NewAuthenticationMiddleware(live *registryconfig.Reloader) AuthenticationMiddleware {
+	config := live.Current()
+
+	keys, err := registryconfig.NewLive(live, LoadTokenKeys)
+	if err != nil {
+		logging.Fatal("Error loading token keys", "error", err)
+	}
+
+	staticTokens, err := registryconfig.NewLive(live, LoadStaticTokenStore)
+	if err != nil {
+		logging.Fatal("Error loading static tokens", "error", err)
+	}
+
+	certificates, err := LoadClientCertificateMapper(config)
+	if err != nil {
+		logging.Fatal("Error loading client certificate identities", "error", err)
+	}
+
 	return &Authentication{
-		Config: config,
+		Config:       config,
+		Keys:         keys,
+		StaticTokens: staticTokens,
+		Certificates: certificates,
 	}
 }
 
@@ -25,6 +50,13 @@ func (a *Authentication) AuthenticationHandlerMiddleware(next http.Handler) http
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
+			if a.Certificates != nil {
+				if identity, ok := a.Certificates.Identify(r); ok {
+					next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), *identity)))
+					return
+				}
+			}
+
 			response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
 				Error: "Authorization header missing",
 			})
@@ -32,7 +64,27 @@ func (a *Authentication) AuthenticationHandlerMiddleware(next http.Handler) http
 		}
 
 		parts := strings.Fields(authHeader)
-		token, err := GetJWTToken(parts[1], []byte(a.Config.TokenEncryptionKey))
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+				Error: "Invalid Authorization header format",
+			})
+			return
+		}
+
+		if staticTokens := a.StaticTokens.Get(); staticTokens != nil && !IsJWT(parts[1]) {
+			entry, ok := staticTokens.Lookup(parts[1])
+			if !ok {
+				response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+					Error: "Invalid token",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), entry.Identity())))
+			return
+		}
+
+		token, err := GetJWTClaimsToken(parts[1], a.Keys.Get())
 		if err != nil {
 			response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
 				Error: "Error parsing token",
@@ -46,5 +98,17 @@ func (a *Authentication) AuthenticationHandlerMiddleware(next http.Handler) http
 			})
 			return
 		}
+
+		ctx := r.Context()
+		if claims, ok := token.Claims.(*RegistryClaims); ok {
+			if len(claims.Organization) > 0 {
+				ctx = context.WithValue(ctx, "organization", claims.Organization)
+			}
+			if login, ok := claims.MapClaims["login"].(string); ok {
+				ctx = context.WithValue(ctx, "login", login)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
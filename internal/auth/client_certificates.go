@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	registryconfig "go-terraform-registry/internal/config"
+	"net/http"
+	"os"
+	"path"
+)
+
+type ClientCertificateConfig struct {
+	Identities []ClientCertificateIdentity `json:"identities"`
+}
+
+// ClientCertificateIdentity maps certificates to a registry identity. Every
+// non-empty match field is a glob that has to match the certificate.
+type ClientCertificateIdentity struct {
+	Name          string   `json:"name"`
+	Subject       string   `json:"subject"`
+	CommonName    string   `json:"common_name"`
+	DNSName       string   `json:"dns_name"`
+	URI           string   `json:"uri"`
+	Email         string   `json:"email"`
+	Organizations []string `json:"organizations"`
+	Role          string   `json:"role"`
+}
+
+type ClientCertificateMapper struct {
+	Config ClientCertificateConfig
+}
+
+func LoadClientCertificateMapper(config registryconfig.RegistryConfig) (*ClientCertificateMapper, error) {
+	if config.TLSClientIdentityFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(config.TLSClientIdentityFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client identity file: %w", err)
+	}
+
+	var certificateConfig ClientCertificateConfig
+	err = json.Unmarshal(data, &certificateConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing client identity file: %w", err)
+	}
+
+	for i, identity := range certificateConfig.Identities {
+		if len(identity.Organizations) == 0 {
+			return nil, fmt.Errorf("client identity %q is missing organizations", identity.Name)
+		}
+		if identity.Subject == "" && identity.CommonName == "" && identity.DNSName == "" && identity.URI == "" && identity.Email == "" {
+			return nil, fmt.Errorf("client identity %q does not match on any certificate field", identity.Name)
+		}
+		if identity.Role == "" {
+			certificateConfig.Identities[i].Role = RoleRead
+		} else if identity.Role != RoleRead && identity.Role != RoleWrite {
+			return nil, fmt.Errorf("client identity %q has unknown role %q", identity.Name, identity.Role)
+		}
+	}
+
+	return &ClientCertificateMapper{
+		Config: certificateConfig,
+	}, nil
+}
+
+// Identify maps the verified client certificate of the request, if any, to
+// the first matching identity.
+func (m *ClientCertificateMapper) Identify(r *http.Request) (*Identity, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	for _, identity := range m.Config.Identities {
+		if !identity.matches(cert) {
+			continue
+		}
+
+		login := identity.Name
+		if login == "" {
+			login = cert.Subject.CommonName
+		}
+
+		return &Identity{
+			Login:         login,
+			Organizations: identity.Organizations,
+			Role:          identity.Role,
+		}, true
+	}
+
+	return nil, false
+}
+
+func (i ClientCertificateIdentity) matches(cert *x509.Certificate) bool {
+	if i.Subject != "" && !globMatch(i.Subject, cert.Subject.String()) {
+		return false
+	}
+	if i.CommonName != "" && !globMatch(i.CommonName, cert.Subject.CommonName) {
+		return false
+	}
+	if i.DNSName != "" && !globMatchAny(i.DNSName, cert.DNSNames) {
+		return false
+	}
+	if i.Email != "" && !globMatchAny(i.Email, cert.EmailAddresses) {
+		return false
+	}
+	if i.URI != "" {
+		var uris []string
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		if !globMatchAny(i.URI, uris) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func globMatch(pattern string, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func globMatchAny(pattern string, values []string) bool {
+	for _, value := range values {
+		if globMatch(pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
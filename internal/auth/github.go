@@ -3,13 +3,13 @@ package auth
 import (
 	"context"
 	"github.com/google/go-github/v69/github"
-	"log"
+	"log/slog"
 )
 
 func GetGitHubUserName(ctx context.Context, client *github.Client, token string) (*string, error) {
 	user, _, err := client.Users.Get(ctx, "")
 	if err != nil {
-		log.Printf("Error getting user: %v", err)
+		slog.ErrorContext(ctx, "Error getting GitHub user", "error", err)
 		return nil, err
 	}
 
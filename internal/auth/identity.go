@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Identity is a caller authenticated by something other than a registry JWT,
+// such as a static token or a client certificate.
+type Identity struct {
+	Login         string
+	Organizations []string
+	Role          string
+}
+
+// Permits reports whether the identity may make a request with the method.
+func (i Identity) Permits(method string) bool {
+	if i.Role != RoleRead {
+		return true
+	}
+
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	ctx = context.WithValue(ctx, "organization", identity.Organizations)
+	ctx = context.WithValue(ctx, "login", identity.Login)
+	return context.WithValue(ctx, "role", identity.Role)
+}
@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	registryconfig "go-terraform-registry/internal/config"
+	"math/big"
+	"os"
+)
+
+// DefaultSecretKeyID is the key id of tokens signed with the shared secret
+// unless another one is configured. Unlike a digest it says nothing about the
+// secret.
+const DefaultSecretKeyID = "hmac"
+
+type TokenKeys struct {
+	SigningMethod    jwt.SigningMethod
+	SigningKey       any
+	SigningKeyID     string
+	VerificationKeys map[string]any
+
+	// secret verifies tokens issued before key ids were added
+	secret []byte
+}
+
+func LoadTokenKeys(config registryconfig.RegistryConfig) (*TokenKeys, error) {
+	return NewTokenKeys(config.TokenSigningMethod, config.TokenSigningKeyFile, config.TokenVerificationKeyFiles, []byte(config.TokenEncryptionKey), config.TokenEncryptionKeyID, config.TokenAcceptHMAC)
+}
+
+// NewTokenKeys loads the keys signing and verifying registry tokens. The
+// shared secret signs tokens with an HMAC method. With an asymmetric method it
+// only verifies tokens when acceptSecret is set, to let tokens signed before
+// the switch expire.
+func NewTokenKeys(method string, signingKeyFile string, verificationKeyFiles []string, secret []byte, secretKeyID string, acceptSecret bool) (*TokenKeys, error) {
+	if method == "" {
+		method = jwt.SigningMethodHS256.Alg()
+	}
+
+	signingMethod := jwt.GetSigningMethod(method)
+	if signingMethod == nil || signingMethod.Alg() == "none" {
+		return nil, fmt.Errorf("unsupported token signing method: %s", method)
+	}
+
+	keys := &TokenKeys{
+		SigningMethod:    signingMethod,
+		VerificationKeys: make(map[string]any),
+	}
+
+	_, hmacSigning := signingMethod.(*jwt.SigningMethodHMAC)
+	if len(secret) > 0 && (hmacSigning || acceptSecret) {
+		if secretKeyID == "" {
+			secretKeyID = DefaultSecretKeyID
+		}
+		keys.VerificationKeys[secretKeyID] = secret
+		keys.secret = secret
+		if hmacSigning {
+			keys.SigningKey = secret
+			keys.SigningKeyID = secretKeyID
+		}
+	}
+
+	if !hmacSigning {
+		if signingKeyFile == "" {
+			return nil, fmt.Errorf("token signing method %s requires a signing key file", method)
+		}
+
+		privateKey, err := readPrivateKey(signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if !keyMatchesMethod(privateKey.Public(), signingMethod) {
+			return nil, fmt.Errorf("signing key %s cannot be used with %s", signingKeyFile, method)
+		}
+
+		kid, err := publicKeyID(privateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+		keys.SigningKey = privateKey
+		keys.SigningKeyID = kid
+		keys.VerificationKeys[kid] = privateKey.Public()
+	}
+
+	if keys.SigningKey == nil {
+		return nil, fmt.Errorf("no token signing key configured")
+	}
+
+	for _, file := range verificationKeyFiles {
+		if file == "" {
+			continue
+		}
+
+		publicKey, err := readPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		kid, err := publicKeyID(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		keys.VerificationKeys[kid] = publicKey
+	}
+
+	return keys, nil
+}
+
+func (k *TokenKeys) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.SigningMethod, claims)
+	token.Header["kid"] = k.SigningKeyID
+
+	return token.SignedString(k.SigningKey)
+}
+
+func (k *TokenKeys) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	var key any
+	if kid == "" {
+		// Tokens issued before key ids were added are HMAC signed
+		if k.secret != nil {
+			key = k.secret
+		}
+	} else {
+		key = k.VerificationKeys[kid]
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	if secret, ok := key.([]byte); ok {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return secret, nil
+	}
+
+	if !keyMatchesMethod(key, token.Method) {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key, nil
+}
+
+func (k *TokenKeys) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{
+		Keys: []JSONWebKey{},
+	}
+
+	for kid, key := range k.VerificationKeys {
+		jwk, err := toJSONWebKey(key)
+		if err != nil {
+			continue
+		}
+		jwk.Kid = kid
+		jwk.Use = "sig"
+		if kid == k.SigningKeyID {
+			jwk.Alg = k.SigningMethod.Alg()
+		}
+		set.Keys = append(set.Keys, *jwk)
+	}
+
+	return set
+}
+
+func keyMatchesMethod(key any, method jwt.SigningMethod) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	case ed25519.PublicKey:
+		_, ok := method.(*jwt.SigningMethodEd25519)
+		return ok
+	}
+
+	return false
+}
+
+func readPEM(filePath string) (*pem.Block, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", filePath)
+	}
+
+	return block, nil
+}
+
+func readPrivateKey(filePath string) (crypto.Signer, error) {
+	block, err := readPEM(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKey(block)
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block: %s", block.Type)
+}
+
+func readPublicKey(filePath string) (any, error) {
+	block, err := readPEM(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+
+	privateKey, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return privateKey.Public(), nil
+}
+
+func toJSONWebKey(key any) (*JSONWebKey, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return &JSONWebKey{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JSONWebKey{
+			Kty: "EC",
+			Crv: k.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return &JSONWebKey{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported public key type %T", key)
+}
+
+// publicKeyID returns the RFC 7638 thumbprint of the key.
+func publicKeyID(key any) (string, error) {
+	jwk, err := toJSONWebKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
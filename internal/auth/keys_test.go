@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-encryption-key"
+
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func signHMAC(t *testing.T, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "bob"})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	return signed
+}
+
+func TestTokenKeysSecretKeyID(t *testing.T) {
+	tests := []struct {
+		name        string
+		secretKeyID string
+		want        string
+	}{
+		{"default", "", DefaultSecretKeyID},
+		{"configured", "2024-01", "2024-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewTokenKeys("HS256", "", nil, []byte(testSecret), tt.secretKeyID, false)
+			if err != nil {
+				t.Fatalf("NewTokenKeys() error = %v", err)
+			}
+			if keys.SigningKeyID != tt.want {
+				t.Errorf("SigningKeyID = %q, want %q", keys.SigningKeyID, tt.want)
+			}
+
+			signed, err := keys.Sign(jwt.MapClaims{"sub": "bob"})
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if _, err := GetJWTToken(signed, keys); err != nil {
+				t.Errorf("GetJWTToken() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenKeysAcceptSecret(t *testing.T) {
+	signingKeyFile := writeSigningKey(t)
+
+	tests := []struct {
+		name         string
+		method       string
+		acceptSecret bool
+		want         bool
+	}{
+		{"hmac signing", "HS256", false, true},
+		{"asymmetric signing", "ES256", false, false},
+		{"asymmetric signing accepting hmac", "ES256", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyFile := ""
+			if tt.method != "HS256" {
+				keyFile = signingKeyFile
+			}
+			keys, err := NewTokenKeys(tt.method, keyFile, nil, []byte(testSecret), "", tt.acceptSecret)
+			if err != nil {
+				t.Fatalf("NewTokenKeys() error = %v", err)
+			}
+
+			for _, kid := range []string{"", DefaultSecretKeyID} {
+				_, err := GetJWTToken(signHMAC(t, kid), keys)
+				if got := err == nil; got != tt.want {
+					t.Errorf("HMAC token with kid %q accepted = %v, want %v: %v", kid, got, tt.want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type PendingAuthorization struct {
+	ClientID      string
+	ClientState   string
+	RedirectURI   string
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+type IssuedAuthorization struct {
+	ClientID      string
+	RedirectURI   string
+	CodeChallenge string
+	Login         string
+	ExpiresAt     time.Time
+}
+
+const (
+	// MaxPendingLifetime and MaxIssuedLifetime bound how long an entry is
+	// kept, whatever expiry the caller asked for.
+	MaxPendingLifetime = 10 * time.Minute
+	MaxIssuedLifetime  = time.Minute
+
+	// MaxAuthorizations caps the entries of each map. Starting an
+	// authorization needs no credentials, so without a cap anyone could grow
+	// the store until the registry runs out of memory.
+	MaxAuthorizations = 10000
+)
+
+var ErrTooManyAuthorizations = errors.New("too many authorizations in progress")
+
+// AuthorizationStore keeps in-flight login.v1 authorizations in memory. Both
+// maps are keyed by single use random values and entries expire quickly.
+type AuthorizationStore struct {
+	mu      sync.Mutex
+	pending map[string]PendingAuthorization
+	issued  map[string]IssuedAuthorization
+}
+
+func NewAuthorizationStore() *AuthorizationStore {
+	return &AuthorizationStore{
+		pending: make(map[string]PendingAuthorization),
+		issued:  make(map[string]IssuedAuthorization),
+	}
+}
+
+func (s *AuthorizationStore) AddPending(pending PendingAuthorization) (string, error) {
+	state, err := RandomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if len(s.pending) >= MaxAuthorizations {
+		return "", ErrTooManyAuthorizations
+	}
+	pending.ExpiresAt = boundExpiry(pending.ExpiresAt, MaxPendingLifetime)
+	s.pending[state] = pending
+
+	return state, nil
+}
+
+func (s *AuthorizationStore) TakePending(state string) (*PendingAuthorization, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+
+	pending, ok := s.pending[state]
+	if !ok {
+		return nil, false
+	}
+	delete(s.pending, state)
+
+	return &pending, true
+}
+
+func (s *AuthorizationStore) AddIssued(issued IssuedAuthorization) (string, error) {
+	code, err := RandomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if len(s.issued) >= MaxAuthorizations {
+		return "", ErrTooManyAuthorizations
+	}
+	issued.ExpiresAt = boundExpiry(issued.ExpiresAt, MaxIssuedLifetime)
+	s.issued[code] = issued
+
+	return code, nil
+}
+
+func (s *AuthorizationStore) TakeIssued(code string) (*IssuedAuthorization, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+
+	issued, ok := s.issued[code]
+	if !ok {
+		return nil, false
+	}
+	delete(s.issued, code)
+
+	return &issued, true
+}
+
+func (s *AuthorizationStore) expire() {
+	now := time.Now()
+	for k, v := range s.pending {
+		if now.After(v.ExpiresAt) {
+			delete(s.pending, k)
+		}
+	}
+	for k, v := range s.issued {
+		if now.After(v.ExpiresAt) {
+			delete(s.issued, k)
+		}
+	}
+}
+
+func boundExpiry(expiresAt time.Time, lifetime time.Duration) time.Time {
+	if latest := time.Now().Add(lifetime); expiresAt.IsZero() || expiresAt.After(latest) {
+		return latest
+	}
+
+	return expiresAt
+}
+
+// VerifyPKCE checks a code_verifier against an S256 code_challenge.
+func VerifyPKCE(verifier string, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// IsLoopbackRedirect reports whether the redirect URI points at the local
+// machine, which is the only redirect terraform login will ever request.
+func IsLoopbackRedirect(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme != "http" || u.User != nil || u.Fragment != "" {
+		return false
+	}
+
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestVerifyPKCE(t *testing.T) {
+	verifier := strings.Repeat("v", 43)
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"matching", verifier, challenge(verifier), true},
+		{"other verifier", strings.Repeat("w", 43), challenge(verifier), false},
+		{"plain challenge", verifier, verifier, false},
+		{"too short", strings.Repeat("v", 42), challenge(strings.Repeat("v", 42)), false},
+		{"too long", strings.Repeat("v", 129), challenge(strings.Repeat("v", 129)), false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPKCE(tt.verifier, tt.challenge); got != tt.want {
+				t.Errorf("VerifyPKCE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackRedirect(t *testing.T) {
+	tests := []struct {
+		redirectURI string
+		want        bool
+	}{
+		{"http://localhost:10000/login", true},
+		{"http://LOCALHOST/login", true},
+		{"http://127.0.0.1:10000/login", true},
+		{"http://[::1]:10000/login", true},
+		{"https://localhost/login", false},
+		{"http://example.com/login", false},
+		{"http://localhost.example.com/login", false},
+		{"http://user@localhost/login", false},
+		{"http://localhost/login#fragment", false},
+		{"http://10.0.0.1/login", false},
+		{"localhost/login", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.redirectURI, func(t *testing.T) {
+			if got := IsLoopbackRedirect(tt.redirectURI); got != tt.want {
+				t.Errorf("IsLoopbackRedirect(%q) = %v, want %v", tt.redirectURI, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizationStorePendingSingleUse(t *testing.T) {
+	s := NewAuthorizationStore()
+
+	state, err := s.AddPending(PendingAuthorization{ClientState: "client", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("AddPending() error = %v", err)
+	}
+
+	pending, ok := s.TakePending(state)
+	if !ok || pending.ClientState != "client" {
+		t.Fatalf("TakePending() = %v, %v, want the pending authorization", pending, ok)
+	}
+	if _, ok := s.TakePending(state); ok {
+		t.Error("TakePending() succeeded twice for the same state")
+	}
+	if _, ok := s.TakePending("unknown"); ok {
+		t.Error("TakePending() succeeded for an unknown state")
+	}
+}
+
+func TestAuthorizationStoreIssuedSingleUse(t *testing.T) {
+	s := NewAuthorizationStore()
+
+	code, err := s.AddIssued(IssuedAuthorization{Login: "octocat", ExpiresAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("AddIssued() error = %v", err)
+	}
+
+	issued, ok := s.TakeIssued(code)
+	if !ok || issued.Login != "octocat" {
+		t.Fatalf("TakeIssued() = %v, %v, want the issued authorization", issued, ok)
+	}
+	if _, ok := s.TakeIssued(code); ok {
+		t.Error("TakeIssued() succeeded twice for the same code")
+	}
+}
+
+func TestAuthorizationStoreExpiry(t *testing.T) {
+	s := NewAuthorizationStore()
+	expired := time.Now().Add(-time.Second)
+
+	state, err := s.AddPending(PendingAuthorization{ExpiresAt: expired})
+	if err != nil {
+		t.Fatalf("AddPending() error = %v", err)
+	}
+	code, err := s.AddIssued(IssuedAuthorization{ExpiresAt: expired})
+	if err != nil {
+		t.Fatalf("AddIssued() error = %v", err)
+	}
+
+	if _, ok := s.TakePending(state); ok {
+		t.Error("TakePending() returned an expired authorization")
+	}
+	if _, ok := s.TakeIssued(code); ok {
+		t.Error("TakeIssued() returned an expired authorization")
+	}
+}
+
+func TestAuthorizationStoreBoundsExpiry(t *testing.T) {
+	s := NewAuthorizationStore()
+
+	if _, err := s.AddPending(PendingAuthorization{ExpiresAt: time.Now().Add(24 * time.Hour)}); err != nil {
+		t.Fatalf("AddPending() error = %v", err)
+	}
+	if _, err := s.AddIssued(IssuedAuthorization{}); err != nil {
+		t.Fatalf("AddIssued() error = %v", err)
+	}
+
+	for _, pending := range s.pending {
+		if latest := time.Now().Add(MaxPendingLifetime); pending.ExpiresAt.After(latest) {
+			t.Errorf("pending authorization expires at %v, after %v", pending.ExpiresAt, latest)
+		}
+	}
+	for _, issued := range s.issued {
+		if issued.ExpiresAt.IsZero() || issued.ExpiresAt.After(time.Now().Add(MaxIssuedLifetime)) {
+			t.Errorf("issued authorization expires at %v, want within %v", issued.ExpiresAt, MaxIssuedLifetime)
+		}
+	}
+}
+
+func TestAuthorizationStoreCap(t *testing.T) {
+	s := NewAuthorizationStore()
+	expiresAt := time.Now().Add(time.Minute)
+
+	for i := 0; i < MaxAuthorizations; i++ {
+		if _, err := s.AddPending(PendingAuthorization{ExpiresAt: expiresAt}); err != nil {
+			t.Fatalf("AddPending() #%d error = %v", i, err)
+		}
+	}
+	if _, err := s.AddPending(PendingAuthorization{ExpiresAt: expiresAt}); !errors.Is(err, ErrTooManyAuthorizations) {
+		t.Fatalf("AddPending() over the cap error = %v, want %v", err, ErrTooManyAuthorizations)
+	}
+
+	// Expired entries are pruned before the cap is checked
+	for state := range s.pending {
+		s.pending[state] = PendingAuthorization{ExpiresAt: time.Now().Add(-time.Second)}
+		break
+	}
+	if _, err := s.AddPending(PendingAuthorization{ExpiresAt: expiresAt}); err != nil {
+		t.Errorf("AddPending() after an entry expired error = %v", err)
+	}
+}
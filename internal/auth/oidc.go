@@ -0,0 +1,457 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	registryconfig "go-terraform-registry/internal/config"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const jwksRefreshInterval = 5 * time.Minute
+
+// oidcSubjectClaims identify the workload a token was issued to. Every trust
+// policy must constrain one of them, otherwise any repository on a shared
+// platform such as GitHub or GitLab could publish through the policy.
+var oidcSubjectClaims = []string{"sub", "repository", "project_path"}
+
+// oidcPublishRoutes are the only management API routes an OIDC identity may
+// call.
+var oidcPublishRoutes = func() *chi.Mux {
+	routes := chi.NewRouter()
+	publish := func(http.ResponseWriter, *http.Request) {}
+	routes.Post("/api/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions", publish)
+	routes.Post("/api/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms", publish)
+	routes.Post("/api/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/versions", publish)
+	return routes
+}()
+
+type OIDCConfig struct {
+	Issuers       []OIDCIssuer      `json:"issuers"`
+	TrustPolicies []OIDCTrustPolicy `json:"trust_policies"`
+}
+
+type OIDCIssuer struct {
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	JWKSURL  string `json:"jwks_url"`
+}
+
+type OIDCTrustPolicy struct {
+	Issuer       string            `json:"issuer"`
+	Claims       map[string]string `json:"claims"`
+	Organization string            `json:"organization"`
+	Namespaces   []string          `json:"namespaces"`
+}
+
+type OIDCIdentity struct {
+	Issuer       string
+	Subject      string
+	Organization string
+	Namespaces   []string
+}
+
+// Permits reports whether the identity may make a request to the path. OIDC
+// identities can only publish provider and module versions into the
+// namespaces of their trust policy.
+func (i OIDCIdentity) Permits(method string, path string) bool {
+	rctx := chi.NewRouteContext()
+	if !oidcPublishRoutes.Match(rctx, method, path) {
+		return false
+	}
+
+	if !strings.EqualFold(rctx.URLParam("organization"), i.Organization) {
+		return false
+	}
+
+	namespace := rctx.URLParam("namespace")
+	for _, allowed := range i.Namespaces {
+		if strings.EqualFold(allowed, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type OIDCVerifier struct {
+	Config OIDCConfig
+	Client *http.Client
+
+	mu   sync.Mutex
+	keys map[string]*jwks
+}
+
+type jwks struct {
+	keys    map[string]any
+	fetched time.Time
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func LoadOIDCConfig(filePath string) (*OIDCConfig, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading OIDC configuration: %w", err)
+	}
+
+	var config OIDCConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OIDC configuration: %w", err)
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// Validate rejects issuers without an audience and trust policies that do not
+// pin the token to a specific workload.
+func (c OIDCConfig) Validate() error {
+	issuers := make(map[string]bool)
+	for _, issuer := range c.Issuers {
+		if issuer.Issuer == "" {
+			return fmt.Errorf("OIDC issuer is missing an issuer URL")
+		}
+		if issuer.Audience == "" {
+			return fmt.Errorf("OIDC issuer %s is missing an audience", issuer.Issuer)
+		}
+		issuers[strings.TrimSuffix(issuer.Issuer, "/")] = true
+	}
+
+	for _, policy := range c.TrustPolicies {
+		if !issuers[strings.TrimSuffix(policy.Issuer, "/")] {
+			return fmt.Errorf("trust policy references unknown issuer %s", policy.Issuer)
+		}
+		if policy.Organization == "" {
+			return fmt.Errorf("trust policy for issuer %s is missing an organization", policy.Issuer)
+		}
+
+		for name, pattern := range policy.Claims {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("trust policy for issuer %s has an invalid pattern for claim %s: %w", policy.Issuer, name, err)
+			}
+		}
+
+		pinned := false
+		for _, name := range oidcSubjectClaims {
+			if pattern, ok := policy.Claims[name]; ok && strings.Trim(pattern, "*?") != "" {
+				pinned = true
+			}
+		}
+		if !pinned {
+			return fmt.Errorf("trust policy for issuer %s must match one of the %s claims", policy.Issuer, strings.Join(oidcSubjectClaims, ", "))
+		}
+	}
+
+	return nil
+}
+
+// LoadOIDCVerifier returns the verifier for the OIDC configuration file, or
+// nil when none is configured.
+func LoadOIDCVerifier(config registryconfig.RegistryConfig) (*OIDCVerifier, error) {
+	if config.OIDCConfigFile == "" {
+		return nil, nil
+	}
+
+	oidcConfig, err := LoadOIDCConfig(config.OIDCConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("OIDC issuers configured", "issuers", len(oidcConfig.Issuers))
+
+	return NewOIDCVerifier(*oidcConfig), nil
+}
+
+func NewOIDCVerifier(config OIDCConfig) *OIDCVerifier {
+	return &OIDCVerifier{
+		Config: config,
+		Client: &http.Client{Timeout: 10 * time.Second},
+		keys:   make(map[string]*jwks),
+	}
+}
+
+// Handles reports whether the unverified token was issued by one of the
+// configured OIDC issuers.
+func (v *OIDCVerifier) Handles(signedToken string) bool {
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(signedToken, claims)
+	if err != nil {
+		return false
+	}
+
+	issuer, _ := claims.GetIssuer()
+	return v.issuer(issuer) != nil
+}
+
+func (v *OIDCVerifier) Verify(ctx context.Context, signedToken string) (*OIDCIdentity, error) {
+	claims := jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(signedToken, claims)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing token: %s", err)
+	}
+
+	issuerName, _ := claims.GetIssuer()
+	issuer := v.issuer(issuerName)
+	if issuer == nil {
+		return nil, fmt.Errorf("untrusted issuer: %s", issuerName)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithIssuer(issuer.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithAudience(issuer.Audience),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+	}
+
+	verified := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(signedToken, verified, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, *issuer, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing token: %s", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	subject, _ := verified.GetSubject()
+	for _, policy := range v.Config.TrustPolicies {
+		if policy.Issuer != issuer.Issuer || !policy.matches(verified) {
+			continue
+		}
+
+		namespaces := policy.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{policy.Organization}
+		}
+
+		return &OIDCIdentity{
+			Issuer:       issuer.Issuer,
+			Subject:      subject,
+			Organization: policy.Organization,
+			Namespaces:   namespaces,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no trust policy matches token for subject %s", subject)
+}
+
+func (p OIDCTrustPolicy) matches(claims jwt.MapClaims) bool {
+	if len(p.Claims) == 0 {
+		return false
+	}
+
+	for name, pattern := range p.Claims {
+		value, ok := claims[name]
+		if !ok {
+			return false
+		}
+
+		matched, err := path.Match(pattern, fmt.Sprint(value))
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *OIDCVerifier) issuer(name string) *OIDCIssuer {
+	for i := range v.Config.Issuers {
+		if strings.TrimSuffix(v.Config.Issuers[i].Issuer, "/") == strings.TrimSuffix(name, "/") {
+			return &v.Config.Issuers[i]
+		}
+	}
+
+	return nil
+}
+
+func (v *OIDCVerifier) key(ctx context.Context, issuer OIDCIssuer, kid string) (any, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	cached := v.keys[issuer.Issuer]
+	if cached != nil {
+		if key, ok := cached.lookup(kid); ok {
+			return key, nil
+		}
+		// Unknown key id, refresh unless the set was fetched recently
+		if time.Since(cached.fetched) < jwksRefreshInterval/10 {
+			return nil, fmt.Errorf("unknown key id: %s", kid)
+		}
+	}
+
+	fetched, err := v.fetchKeys(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	v.keys[issuer.Issuer] = fetched
+
+	if key, ok := fetched.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key id: %s", kid)
+}
+
+func (j *jwks) lookup(kid string) (any, bool) {
+	if time.Since(j.fetched) > jwksRefreshInterval {
+		return nil, false
+	}
+
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func (v *OIDCVerifier) fetchKeys(ctx context.Context, issuer OIDCIssuer) (*jwks, error) {
+	jwksURL := issuer.JWKSURL
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		discoveryURL := fmt.Sprintf("%s/.well-known/openid-configuration", strings.TrimSuffix(issuer.Issuer, "/"))
+		err := v.getJSON(ctx, discoveryURL, &discovery)
+		if err != nil {
+			return nil, err
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	var set JSONWebKeySet
+	err := v.getJSON(ctx, jwksURL, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return &jwks{
+		keys:    keys,
+		fetched: time.Now(),
+	}, nil
+}
+
+func (v *OIDCVerifier) getJSON(ctx context.Context, url string, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %w", url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error fetching %s: status %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (k JSONWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+}
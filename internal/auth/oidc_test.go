@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOIDCConfigValidate(t *testing.T) {
+	issuer := OIDCIssuer{Issuer: "https://token.actions.githubusercontent.com", Audience: "https://registry.example.com"}
+	policy := func(claims map[string]string) OIDCTrustPolicy {
+		return OIDCTrustPolicy{Issuer: issuer.Issuer, Claims: claims, Organization: "default"}
+	}
+
+	tests := []struct {
+		name    string
+		config  OIDCConfig
+		wantErr string
+	}{
+		{
+			name:   "repository pinned",
+			config: OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(map[string]string{"repository": "acme/*"})}},
+		},
+		{
+			name:   "subject pinned",
+			config: OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(map[string]string{"sub": "repo:acme/infra:ref:refs/heads/main"})}},
+		},
+		{
+			name:    "missing audience",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{{Issuer: issuer.Issuer}}},
+			wantErr: "missing an audience",
+		},
+		{
+			name:    "no claims",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(nil)}},
+			wantErr: "must match one of",
+		},
+		{
+			name:    "unpinned claims",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(map[string]string{"ref": "refs/heads/main"})}},
+			wantErr: "must match one of",
+		},
+		{
+			name:    "wildcard subject",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(map[string]string{"repository": "*"})}},
+			wantErr: "must match one of",
+		},
+		{
+			name:    "bad pattern",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{policy(map[string]string{"repository": "acme/["})}},
+			wantErr: "invalid pattern",
+		},
+		{
+			name:    "unknown issuer",
+			config:  OIDCConfig{Issuers: []OIDCIssuer{issuer}, TrustPolicies: []OIDCTrustPolicy{{Issuer: "https://gitlab.com", Claims: map[string]string{"sub": "x"}, Organization: "default"}}},
+			wantErr: "unknown issuer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOIDCIdentityPermits(t *testing.T) {
+	identity := OIDCIdentity{Organization: "acme", Namespaces: []string{"infra"}}
+
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{"POST", "/api/v2/organizations/acme/registry-providers/private/infra/aws/versions", true},
+		{"POST", "/api/v2/organizations/ACME/registry-providers/private/Infra/aws/versions/1.0.0/platforms", true},
+		{"POST", "/api/v2/organizations/acme/registry-modules/private/infra/vpc/aws/versions", true},
+		{"POST", "/api/v2/organizations/acme/registry-providers/private/other/aws/versions", false},
+		{"POST", "/api/v2/organizations/other/registry-providers/private/infra/aws/versions", false},
+		{"GET", "/api/v2/organizations/acme/registry-providers/private/infra/aws/versions", false},
+		{"DELETE", "/api/v2/organizations/acme/registry-providers/private/infra/aws/versions/1.0.0", false},
+		{"PATCH", "/api/v2/organizations/acme", false},
+		{"DELETE", "/api/v2/organizations/acme", false},
+		{"POST", "/api/v2/organizations/acme/webhooks", false},
+		{"POST", "/api/v2/organizations/acme/registry-shares", false},
+		{"GET", "/api/v2/organizations/acme/audit-trail/export", false},
+		{"POST", "/api/registry/private/v2/gpg-keys", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if got := identity.Permits(tt.method, tt.path); got != tt.want {
+				t.Errorf("Permits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	registryconfig "go-terraform-registry/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+
+	staticTokenReloadInterval = 5 * time.Second
+
+	// Static tokens are tfr_<id>_<secret>, the id picks the one entry whose
+	// hash is verified.
+	staticTokenPrefix = "tfr_"
+
+	staticTokenMissTTL   = time.Minute
+	staticTokenMaxMisses = 10000
+)
+
+type StaticTokenConfig struct {
+	Tokens []StaticToken `json:"tokens"`
+}
+
+type StaticToken struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Hash          string     `json:"hash"`
+	Organizations []string   `json:"organizations"`
+	Role          string     `json:"role"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// StaticTokenStore holds the service account tokens from the static token
+// file. The file is re-read when its modification time changes. Since bcrypt
+// and argon2 are deliberately slow, a token is only verified against the entry
+// named by its id, and the outcome is cached by digest: hits until the next
+// reload so revoked tokens stop working, misses for a short while.
+type StaticTokenStore struct {
+	Path string
+
+	mu         sync.Mutex
+	tokens     map[string]StaticToken
+	generation uint64
+	modTime    time.Time
+	checkedAt  time.Time
+	verified   map[string]string
+	misses     map[string]time.Time
+}
+
+func LoadStaticTokenStore(config registryconfig.RegistryConfig) (*StaticTokenStore, error) {
+	if config.StaticTokenFile == "" {
+		return nil, nil
+	}
+
+	return NewStaticTokenStore(config.StaticTokenFile)
+}
+
+func NewStaticTokenStore(filePath string) (*StaticTokenStore, error) {
+	s := &StaticTokenStore{
+		Path: filePath,
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading static token file: %w", err)
+	}
+
+	err = s.load(info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Lookup returns the static token entry matching the presented token. Expired
+// entries are never returned. The hash is verified without holding the lock,
+// so slow verifications do not hold up other lookups.
+func (s *StaticTokenStore) Lookup(token string) (*StaticToken, bool) {
+	id, ok := ParseStaticToken(token)
+	if !ok {
+		return nil, false
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	digest := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	s.reload()
+
+	entry, ok := s.tokens[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, false
+	}
+	if expires, missed := s.misses[digest]; missed && time.Now().Before(expires) {
+		s.mu.Unlock()
+		return nil, false
+	}
+	verified := s.verified[digest] == id
+	generation := s.generation
+	s.mu.Unlock()
+
+	if !verified {
+		verified = verifyTokenHash(token, entry.Hash)
+
+		s.mu.Lock()
+		if generation == s.generation {
+			if verified {
+				s.verified[digest] = id
+			} else {
+				s.miss(digest)
+			}
+		}
+		s.mu.Unlock()
+
+		if !verified {
+			return nil, false
+		}
+	}
+
+	if entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// miss caches a failed verification, expired misses are dropped once there
+// are too many of them and all of them if that is not enough.
+func (s *StaticTokenStore) miss(digest string) {
+	now := time.Now()
+
+	if len(s.misses) >= staticTokenMaxMisses {
+		for key, expires := range s.misses {
+			if now.After(expires) {
+				delete(s.misses, key)
+			}
+		}
+	}
+	if len(s.misses) >= staticTokenMaxMisses {
+		clear(s.misses)
+	}
+
+	s.misses[digest] = now.Add(staticTokenMissTTL)
+}
+
+func (s *StaticTokenStore) reload() {
+	if time.Since(s.checkedAt) < staticTokenReloadInterval {
+		return
+	}
+	s.checkedAt = time.Now()
+
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		slog.Warn("Unable to stat static token file", "path", s.Path, "error", err)
+		return
+	}
+
+	if info.ModTime().Equal(s.modTime) {
+		return
+	}
+
+	// Keep serving the previous tokens if the new file is invalid
+	err = s.load(info.ModTime())
+	if err != nil {
+		slog.Warn("Unable to reload static token file", "path", s.Path, "error", err)
+		return
+	}
+
+	slog.Info("Reloaded static token file", "path", s.Path, "tokens", len(s.tokens))
+}
+
+func (s *StaticTokenStore) load(modTime time.Time) error {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return fmt.Errorf("error reading static token file: %w", err)
+	}
+
+	var config StaticTokenConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return fmt.Errorf("error parsing static token file: %w", err)
+	}
+
+	tokens := make(map[string]StaticToken, len(config.Tokens))
+	for _, entry := range config.Tokens {
+		if entry.ID == "" {
+			return fmt.Errorf("static token %q is missing an id, generate a new token with tfrepoctl token hash", entry.Name)
+		}
+		if _, ok := tokens[entry.ID]; ok {
+			return fmt.Errorf("static token %q reuses id %q", entry.Name, entry.ID)
+		}
+		if entry.Hash == "" {
+			return fmt.Errorf("static token %q is missing a hash", entry.Name)
+		}
+		if len(entry.Organizations) == 0 {
+			return fmt.Errorf("static token %q is missing organizations", entry.Name)
+		}
+		if entry.Role == "" {
+			entry.Role = RoleRead
+		} else if entry.Role != RoleRead && entry.Role != RoleWrite {
+			return fmt.Errorf("static token %q has unknown role %q", entry.Name, entry.Role)
+		}
+		tokens[entry.ID] = entry
+	}
+
+	s.tokens = tokens
+	s.generation++
+	s.modTime = modTime
+	s.verified = make(map[string]string)
+	s.misses = make(map[string]time.Time)
+
+	return nil
+}
+
+// GenerateStaticToken returns a new static token and its id.
+func GenerateStaticToken() (string, string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret, err := RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return staticTokenPrefix + hex.EncodeToString(id) + "_" + secret, hex.EncodeToString(id), nil
+}
+
+// ParseStaticToken returns the id of a static token, the id is not secret.
+func ParseStaticToken(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, staticTokenPrefix)
+	if !ok {
+		return "", false
+	}
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", false
+	}
+
+	return id, true
+}
+
+// IsJWT reports whether the token has the three segment JWT structure, so
+// opaque static tokens can be told apart without attempting to verify them.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+func HashStaticToken(token string, algorithm string) (string, error) {
+	switch algorithm {
+	case "bcrypt":
+		hash, err := bcrypt.GenerateFromPassword([]byte(token), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	case "argon2id", "":
+		salt := make([]byte, 16)
+		_, err := rand.Read(salt)
+		if err != nil {
+			return "", err
+		}
+		var memory uint32 = 64 * 1024
+		var iterations uint32 = 3
+		var parallelism uint8 = 2
+		key := argon2.IDKey([]byte(token), salt, iterations, memory, parallelism, 32)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, memory, iterations, parallelism,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+
+	return "", fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+}
+
+func verifyTokenHash(token string, hash string) bool {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return verifyArgon2id(token, hash)
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(token)) == nil
+}
+
+func verifyArgon2id(token string, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism)
+	if err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(token), salt, iterations, memory, parallelism, uint32(len(expected)))
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+func (t StaticToken) Identity() Identity {
+	return Identity{
+		Login:         t.Name,
+		Organizations: t.Organizations,
+		Role:          t.Role,
+	}
+}
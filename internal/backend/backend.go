@@ -2,9 +2,13 @@ package backend
 
 import (
 	"context"
+	"errors"
 	apimodels "go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/events"
 	"go-terraform-registry/internal/models"
 	registrytypes "go-terraform-registry/internal/types"
+	"time"
 )
 
 type Backend struct {
@@ -15,6 +19,19 @@ type Backend struct {
 	ModulesBackend
 	ModuleVersionsBackend
 	GPGKeysBackend
+	NamespacesBackend
+	SharesBackend
+	OrganizationsBackend
+	AuditBackend
+	WebhooksBackend
+	EventsBackend
+	DownloadsBackend
+	// RateLimitsBackend is only set by backends able to share rate limits
+	// between replicas.
+	RateLimitsBackend
+	// CacheInvalidationBackend is only set by backends able to tell replicas
+	// their cached reads are stale.
+	CacheInvalidationBackend
 }
 
 type RegistryBackend interface {
@@ -52,7 +69,125 @@ type GPGKeysBackend interface {
 	GPGKeysAdd(ctx context.Context, request apimodels.GPGKeysRequest) (*apimodels.GPGKeysResponse, error)
 }
 
+type NamespacesBackend interface {
+	NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.NamespacesResponse, error)
+	NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.NamespacesRequest) (*apimodels.NamespacesResponse, error)
+}
+
+type SharesBackend interface {
+	SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.SharesListResponse, error)
+	SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.SharesRequest) (*apimodels.SharesResponse, error)
+	SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error)
+	SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error)
+}
+
+type OrganizationsBackend interface {
+	OrganizationsList(ctx context.Context) (*apimodels.OrganizationsListResponse, error)
+	OrganizationsCreate(ctx context.Context, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error)
+	OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.OrganizationsResponse, error)
+	OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error)
+	OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error)
+}
+
+type AuditBackend interface {
+	AuditEventsCreate(ctx context.Context, event registrytypes.AuditEvent) error
+	AuditEventsList(ctx context.Context, parameters registrytypes.AuditParameters) (*apimodels.AuditEventsListResponse, error)
+	AuditEventsExport(ctx context.Context, parameters registrytypes.AuditParameters) ([]chain.Record, error)
+}
+
+// ErrSigningKeyNotFound is returned when a provider version is signed with a
+// key that is not registered for its namespace.
+var ErrSigningKeyNotFound = errors.New("gpg key not found")
+
+// WebhookDeliveryRetention is the number of delivery attempts kept per
+// webhook, older attempts are pruned on insert.
+const WebhookDeliveryRetention = 100
+
+// EventLogRetention is the number of registry events kept per organization for
+// resuming event streams, older events are pruned on insert.
+const EventLogRetention = 1000
+
+// DownloadCounters returns the counters incremented by a download: the total,
+// the day and the Terraform version, plus the platform for providers.
+func DownloadCounters(download registrytypes.Download) []registrytypes.DownloadCounter {
+	counters := []registrytypes.DownloadCounter{
+		{Version: download.Version, Dimension: registrytypes.DownloadDimensionTotal},
+		{Version: download.Version, Dimension: registrytypes.DownloadDimensionDay, Key: download.Timestamp.UTC().Format(time.DateOnly)},
+		{Version: download.Version, Dimension: registrytypes.DownloadDimensionTerraform, Key: download.TerraformVersion},
+	}
+	if download.OS != "" {
+		counters = append(counters, registrytypes.DownloadCounter{
+			Version:   download.Version,
+			Dimension: registrytypes.DownloadDimensionPlatform,
+			Key:       download.OS + "_" + download.Architecture,
+		})
+	}
+
+	return counters
+}
+
+type WebhooksBackend interface {
+	WebhooksList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.WebhooksListResponse, error)
+	WebhooksCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.WebhooksRequest) (*apimodels.WebhooksResponse, error)
+	WebhooksGet(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (*apimodels.WebhooksResponse, error)
+	WebhooksUpdate(ctx context.Context, parameters registrytypes.APIParameters, webhookID string, request apimodels.WebhooksRequest) (*apimodels.WebhooksResponse, error)
+	WebhooksDelete(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (int, error)
+	WebhooksSubscribed(ctx context.Context, organization string) ([]registrytypes.Webhook, error)
+	WebhookDeliveriesCreate(ctx context.Context, delivery registrytypes.WebhookDelivery) error
+	WebhookDeliveriesList(ctx context.Context, parameters registrytypes.WebhookDeliveryParameters) (*apimodels.WebhookDeliveriesListResponse, error)
+}
+
+type EventsBackend interface {
+	EventsCreate(ctx context.Context, event events.Event) error
+	// EventsList returns the events logged after lastEventID, oldest first. The
+	// whole log is returned when lastEventID is no longer retained.
+	EventsList(ctx context.Context, organization string, lastEventID string) ([]events.Event, error)
+}
+
+type DownloadsBackend interface {
+	// DownloadsRecord increments the counters a download falls under.
+	DownloadsRecord(ctx context.Context, download registrytypes.Download) error
+	DownloadsList(ctx context.Context, parameters registrytypes.DownloadParameters) ([]registrytypes.DownloadCounter, error)
+}
+
+type RateLimitsBackend interface {
+	// RateLimitsTake takes a token from the bucket of key, created full when
+	// missing.
+	RateLimitsTake(ctx context.Context, key string, limit registrytypes.RateLimit, now time.Time) (registrytypes.RateLimitDecision, error)
+	// RateLimitsPrune drops the buckets not used since before.
+	RateLimitsPrune(ctx context.Context, before time.Time) error
+}
+
+// TakeRateLimitToken refills bucket for the time elapsed since its last use
+// and takes a token from it if one is available.
+func TakeRateLimitToken(bucket registrytypes.RateLimitBucket, limit registrytypes.RateLimit, now time.Time) (registrytypes.RateLimitBucket, registrytypes.RateLimitDecision) {
+	tokens := float64(limit.Burst)
+	if !bucket.Updated.IsZero() {
+		elapsed := max(now.Sub(bucket.Updated).Seconds(), 0)
+		tokens = min(tokens, bucket.Tokens+elapsed*limit.Rate)
+	}
+
+	if tokens >= 1 {
+		return registrytypes.RateLimitBucket{Tokens: tokens - 1, Updated: now}, registrytypes.RateLimitDecision{Allowed: true}
+	}
+
+	wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	return registrytypes.RateLimitBucket{Tokens: tokens, Updated: now}, registrytypes.RateLimitDecision{RetryAfter: wait}
+}
+
+type CacheInvalidationBackend interface {
+	// CacheInvalidationPublish tells every replica that the cached reads under
+	// scope are stale.
+	CacheInvalidationPublish(ctx context.Context, scope string) error
+	// CacheInvalidationListen calls invalidate with the published scopes until
+	// ctx is done. An empty scope means notifications may have been missed and
+	// everything is stale.
+	CacheInvalidationListen(ctx context.Context, invalidate func(scope string)) error
+}
+
 type BackendLifecycle interface {
 	Configure(ctx context.Context) error
 	Close(ctx context.Context) error
+	// HealthCheck returns an error when the backend cannot serve requests.
+	HealthCheck(ctx context.Context) error
 }
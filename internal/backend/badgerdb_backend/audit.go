@@ -0,0 +1,161 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ backend.AuditBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) AuditEventsCreate(ctx context.Context, event registrytypes.AuditEvent) error {
+	value := &AuditEvent{
+		ID:           uuid.New().String(),
+		Organization: event.Organization,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		CreatedAt:    event.Timestamp.UTC(),
+	}
+
+	// Keys sort by time so listing the prefix returns events in order
+	key := func(e AuditEvent) string {
+		return fmt.Sprintf("%s%020d:%s", b.auditPrefix(e.Organization), e.CreatedAt.UnixNano(), e.ID)
+	}
+
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return auditEventAppend(db, b.auditHeadKey(event.Organization), key, value)
+	})
+}
+
+func (b *BadgerDBBackend) AuditEventsList(ctx context.Context, parameters registrytypes.AuditParameters) (*models.AuditEventsListResponse, error) {
+	var events []AuditEvent
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		events, err = auditEventList(db, b.auditPrefix(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []AuditEvent
+	for _, event := range events {
+		if parameters.Since != nil && event.CreatedAt.Before(*parameters.Since) {
+			continue
+		}
+		if parameters.Until != nil && !event.CreatedAt.Before(*parameters.Until) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	resp := &models.AuditEventsListResponse{
+		Data: []models.AuditEventsDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, len(matched)),
+		},
+	}
+
+	start := (parameters.PageNumber - 1) * parameters.PageSize
+	for i := start; i < len(matched) && i < start+parameters.PageSize; i++ {
+		resp.Data = append(resp.Data, auditEventData(matched[i]))
+	}
+
+	return resp, nil
+}
+
+func (b *BadgerDBBackend) AuditEventsExport(ctx context.Context, parameters registrytypes.AuditParameters) ([]chain.Record, error) {
+	var events []AuditEvent
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		events, err = auditEventList(db, b.auditPrefix(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Sequence < events[j].Sequence
+	})
+
+	var records []chain.Record
+	for _, event := range events {
+		// Events recorded before the chain was introduced have no sequence
+		if event.Sequence == 0 {
+			continue
+		}
+		if parameters.Since != nil && event.CreatedAt.Before(*parameters.Since) {
+			continue
+		}
+		if parameters.Until != nil && !event.CreatedAt.Before(*parameters.Until) {
+			continue
+		}
+		records = append(records, auditRecord(event))
+	}
+
+	return records, nil
+}
+
+func (b *BadgerDBBackend) auditPrefix(organization string) string {
+	return fmt.Sprintf("%s:%s:", b.Tables.AuditTableName, strings.ToLower(organization))
+}
+
+func (b *BadgerDBBackend) auditHeadKey(organization string) string {
+	return fmt.Sprintf("%s-head:%s", b.Tables.AuditTableName, strings.ToLower(organization))
+}
+
+func linkAuditEvent(event *AuditEvent, headSequence int64, headHash string) {
+	record := auditRecord(*event)
+	chain.Link(&record, headSequence, headHash)
+
+	event.Sequence = record.Sequence
+	event.PrevHash = record.PrevHash
+	event.Hash = record.Hash
+}
+
+func auditRecord(event AuditEvent) chain.Record {
+	return chain.Record{
+		Sequence:     event.Sequence,
+		ID:           event.ID,
+		Timestamp:    event.CreatedAt.Format(time.RFC3339Nano),
+		Organization: event.Organization,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		PrevHash:     event.PrevHash,
+		Hash:         event.Hash,
+	}
+}
+
+func auditEventData(event AuditEvent) models.AuditEventsDataResponse {
+	return models.AuditEventsDataResponse{
+		ID:   event.ID,
+		Type: "audit-events",
+		Attributes: models.AuditEventsAttributesResponse{
+			Timestamp:    event.CreatedAt.Format(time.RFC3339Nano),
+			Organization: event.Organization,
+			Actor:        event.Actor,
+			Action:       event.Action,
+			Target:       event.Target,
+			SourceIP:     event.SourceIP,
+			Outcome:      event.Outcome,
+			StatusCode:   event.StatusCode,
+		},
+	}
+}
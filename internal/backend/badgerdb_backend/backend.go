@@ -2,10 +2,11 @@ package badgerdb_backend
 
 import (
 	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
 	"go-terraform-registry/internal/backend"
 	"go-terraform-registry/internal/config"
-	"log"
-	"os"
+	"log/slog"
 )
 
 var _ backend.BackendLifecycle = &BadgerDBBackend{}
@@ -21,6 +22,14 @@ type BadgerTables struct {
 	ProviderTableName        string
 	ProviderVersionTableName string
 	ModuleTableName          string
+	NamespaceTableName       string
+	ShareTableName           string
+	OrganizationTableName    string
+	AuditTableName           string
+	WebhookTableName         string
+	WebhookDeliveryTableName string
+	EventTableName           string
+	DownloadTableName        string
 }
 
 func NewBadgerDBBackend(_ context.Context, config config.RegistryConfig) (*backend.Backend, error) {
@@ -36,22 +45,37 @@ func NewBadgerDBBackend(_ context.Context, config config.RegistryConfig) (*backe
 		ModulesBackend:          b,
 		ModuleVersionsBackend:   b,
 		GPGKeysBackend:          b,
+		NamespacesBackend:       b,
+		SharesBackend:           b,
+		OrganizationsBackend:    b,
+		AuditBackend:            b,
+		WebhooksBackend:         b,
+		EventsBackend:           b,
+		DownloadsBackend:        b,
 	}, nil
 }
 
 func (b *BadgerDBBackend) Configure(ctx context.Context) error {
-	b.DBPath = "registry_db"
+	b.DBPath = b.Config.BadgerDB.Path
 	b.Tables.GPGTableName = "gpg"
 	b.Tables.ProviderTableName = "providers"
 	b.Tables.ProviderVersionTableName = "provider-version"
 	b.Tables.ModuleTableName = "modules"
+	b.Tables.NamespaceTableName = "namespaces"
+	b.Tables.ShareTableName = "shares"
+	b.Tables.OrganizationTableName = "organizations"
+	b.Tables.AuditTableName = "audit"
+	b.Tables.WebhookTableName = "webhooks"
+	b.Tables.WebhookDeliveryTableName = "webhook-deliveries"
+	b.Tables.EventTableName = "events"
+	b.Tables.DownloadTableName = "downloads"
 
-	val, ok := os.LookupEnv("BADGER_DB_PATH")
-	if ok {
-		b.DBPath = val
-	}
+	slog.InfoContext(ctx, "Using BadgerDB backend", "path", b.DBPath)
 
-	log.Println("Using BadgerDB for backend.")
+	err := b.organizationsBackfill(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating organizations for existing data: %w", err)
+	}
 
 	return nil
 }
@@ -59,3 +83,11 @@ func (b *BadgerDBBackend) Configure(ctx context.Context) error {
 func (b *BadgerDBBackend) Close(ctx context.Context) error {
 	return nil
 }
+
+func (b *BadgerDBBackend) HealthCheck(ctx context.Context) error {
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return db.View(func(txn *badger.Txn) error {
+			return nil
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"strings"
+)
+
+var _ backend.DownloadsBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) DownloadsRecord(ctx context.Context, download registrytypes.Download) error {
+	prefix := b.downloadPrefix(download.Kind, download.Namespace, download.Name, download.Provider)
+
+	keys := map[string]DownloadCounter{}
+	for _, counter := range backend.DownloadCounters(download) {
+		key := fmt.Sprintf("%s%s:%s:%s", prefix, counter.Version, counter.Dimension, counter.Key)
+		keys[key] = DownloadCounter{
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+		}
+	}
+
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return downloadCountersIncrement(db, keys)
+	})
+}
+
+func (b *BadgerDBBackend) DownloadsList(ctx context.Context, parameters registrytypes.DownloadParameters) ([]registrytypes.DownloadCounter, error) {
+	var counters []DownloadCounter
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		counters, err = downloadCounterList(db, b.downloadPrefix(parameters.Kind, parameters.Namespace, parameters.Name, parameters.Provider))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []registrytypes.DownloadCounter
+	for _, counter := range counters {
+		result = append(result, registrytypes.DownloadCounter{
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+			Count:     counter.Count,
+		})
+	}
+
+	return result, nil
+}
+
+// downloadPrefix keeps the namespace second so organizationKeys finds the
+// counters of an organization.
+func (b *BadgerDBBackend) downloadPrefix(kind string, namespace string, name string, provider string) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s:", b.Tables.DownloadTableName, strings.ToLower(namespace), kind, strings.ToLower(name), strings.ToLower(provider))
+}
@@ -0,0 +1,79 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/events"
+	"strings"
+	"time"
+)
+
+var _ backend.EventsBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) EventsCreate(ctx context.Context, event events.Event) error {
+	value := RegistryEvent{
+		ID:           event.ID,
+		Organization: event.Organization,
+		Type:         event.Type,
+		Subject:      event.Subject,
+		Data:         event.Data,
+		CreatedAt:    event.Timestamp,
+	}
+
+	// Keys sort in insertion order so the oldest events are pruned first
+	prefix := b.eventPrefix(event.Organization)
+	key := fmt.Sprintf("%s%020d:%s", prefix, time.Now().UnixNano(), event.ID)
+
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		err := registryEventSet(db, key, value)
+		if err != nil {
+			return err
+		}
+
+		keys, err := keysWithPrefix(db, prefix)
+		if err != nil || len(keys) <= backend.EventLogRetention {
+			return err
+		}
+
+		return keysDelete(db, keys[:len(keys)-backend.EventLogRetention])
+	})
+}
+
+func (b *BadgerDBBackend) EventsList(ctx context.Context, organization string, lastEventID string) ([]events.Event, error) {
+	var logged []RegistryEvent
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		logged, err = registryEventList(db, b.eventPrefix(organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range logged {
+		if event.ID == lastEventID {
+			logged = logged[i+1:]
+			break
+		}
+	}
+
+	var result []events.Event
+	for _, event := range logged {
+		result = append(result, events.Event{
+			ID:           event.ID,
+			Type:         event.Type,
+			Organization: event.Organization,
+			Subject:      event.Subject,
+			Timestamp:    event.CreatedAt,
+			Data:         event.Data,
+		})
+	}
+
+	return result, nil
+}
+
+func (b *BadgerDBBackend) eventPrefix(organization string) string {
+	return fmt.Sprintf("%s:%s:", b.Tables.EventTableName, strings.ToLower(organization))
+}
@@ -28,7 +28,7 @@ func (b *BadgerDBBackend) GPGKeysAdd(ctx context.Context, request models.GPGKeys
 	}
 
 	key := fmt.Sprintf("%s:%s:%s", b.Tables.GPGTableName, request.Data.Attributes.Namespace, keyId[0])
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return gpgSet(db, key, gpg)
 	})
 	if err != nil {
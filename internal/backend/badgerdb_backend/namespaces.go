@@ -0,0 +1,99 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"strings"
+	"time"
+)
+
+var _ backend.NamespacesBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.NamespacesResponse, error) {
+	ns, err := b.namespaceLookup(ctx, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResponse(ns), nil
+}
+
+func (b *BadgerDBBackend) NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.NamespacesRequest) (*models.NamespacesResponse, error) {
+	ns, err := b.namespaceLookup(ctx, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if ns.ID == "" {
+		ns.ID = uuid.New().String()
+	}
+
+	attributes := request.Data.Attributes
+	if attributes.Public != nil {
+		ns.Public = *attributes.Public
+	}
+	if attributes.PublicModules != nil {
+		ns.PublicModules = *attributes.PublicModules
+	}
+	if attributes.PublicProviders != nil {
+		ns.PublicProviders = *attributes.PublicProviders
+	}
+	ns.UpdatedAt = time.Now().UTC()
+
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return namespaceSet(db, b.namespaceKey(parameters), *ns)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResponse(ns), nil
+}
+
+func (b *BadgerDBBackend) namespaceKey(parameters registrytypes.APIParameters) string {
+	return fmt.Sprintf("%s:%s:%s", b.Tables.NamespaceTableName, strings.ToLower(parameters.Organization), strings.ToLower(parameters.Namespace))
+}
+
+func (b *BadgerDBBackend) namespaceLookup(ctx context.Context, parameters registrytypes.APIParameters) (*Namespace, error) {
+	ns := &Namespace{
+		Organization:    parameters.Organization,
+		Namespace:       parameters.Namespace,
+		PublicModules:   []string{},
+		PublicProviders: []string{},
+	}
+
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return namespaceGet(db, b.namespaceKey(parameters), ns)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func namespaceResponse(ns *Namespace) *models.NamespacesResponse {
+	resp := &models.NamespacesResponse{
+		Data: models.NamespacesDataResponse{
+			ID:   ns.ID,
+			Type: "registry-namespaces",
+			Attributes: models.NamespacesAttributesResponse{
+				Name:            ns.Namespace,
+				Public:          ns.Public,
+				PublicModules:   ns.PublicModules,
+				PublicProviders: ns.PublicProviders,
+			},
+		},
+	}
+
+	if !ns.UpdatedAt.IsZero() {
+		resp.Data.Attributes.UpdatedAt = ns.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
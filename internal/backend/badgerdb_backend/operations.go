@@ -1,15 +1,25 @@
 package badgerdb_backend
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/dgraph-io/badger/v4"
-	"log"
+	"log/slog"
+	"os"
 	"strings"
+	"sync"
 )
 
-func withBadgerDB(dbPath string, fn func(*badger.DB) error) error {
+// badgerMu serializes access within the process, the database is opened per
+// operation and Badger refuses a second open while the directory is locked.
+var badgerMu sync.Mutex
+
+func withBadgerDB(ctx context.Context, dbPath string, fn func(*badger.DB) error) error {
+	badgerMu.Lock()
+	defer badgerMu.Unlock()
+
 	opts := badger.DefaultOptions(dbPath).WithLoggingLevel(badger.ERROR)
 	db, err := badger.Open(opts)
 	if err != nil {
@@ -17,7 +27,8 @@ func withBadgerDB(dbPath string, fn func(*badger.DB) error) error {
 	}
 	defer func() {
 		if err := db.Close(); err != nil {
-			log.Fatal(err)
+			slog.ErrorContext(ctx, "Error closing BadgerDB", "path", dbPath, "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -99,6 +110,407 @@ func gpgGet(db *badger.DB, key string, value *GPGKey) error {
 	})
 }
 
+func namespaceSet(db *badger.DB, key string, value Namespace) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func namespaceGet(db *badger.DB, key string, value *Namespace) error {
+	return db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return item.Value(func(v []byte) error {
+			return json.Unmarshal(v, &value)
+		})
+	})
+}
+
+func shareSet(db *badger.DB, key string, value Share) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func shareList(db *badger.DB, prefix string) ([]Share, error) {
+	var shares []Share
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var share Share
+				if err := json.Unmarshal(v, &share); err != nil {
+					return err
+				}
+				shares = append(shares, share)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return shares, err
+}
+
+func shareDelete(db *badger.DB, key string) (bool, error) {
+	deleted := false
+	err := db.Update(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(key))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		deleted = true
+		return txn.Delete([]byte(key))
+	})
+
+	return deleted, err
+}
+
+func organizationGet(db *badger.DB, key string) (*Organization, error) {
+	var organization *Organization
+	err := db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return item.Value(func(v []byte) error {
+			organization = &Organization{}
+			return json.Unmarshal(v, organization)
+		})
+	})
+
+	return organization, err
+}
+
+func organizationSet(db *badger.DB, key string, value Organization) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func organizationList(db *badger.DB, prefix string) ([]Organization, error) {
+	var organizations []Organization
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var organization Organization
+				if err := json.Unmarshal(v, &organization); err != nil {
+					return err
+				}
+				organizations = append(organizations, organization)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return organizations, err
+}
+
+// auditEventAppend links value to the event stored at headKey and writes both
+// in one transaction, so concurrent writers cannot fork the chain.
+func auditEventAppend(db *badger.DB, headKey string, key func(AuditEvent) string, value *AuditEvent) error {
+	return db.Update(func(txn *badger.Txn) error {
+		var head AuditEvent
+		item, err := txn.Get([]byte(headKey))
+		if err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		if err == nil {
+			err = item.Value(func(v []byte) error {
+				return json.Unmarshal(v, &head)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		linkAuditEvent(value, head.Sequence, head.Hash)
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if err := txn.Set([]byte(key(*value)), data); err != nil {
+			return err
+		}
+		return txn.Set([]byte(headKey), data)
+	})
+}
+
+func auditEventList(db *badger.DB, prefix string) ([]AuditEvent, error) {
+	var events []AuditEvent
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var event AuditEvent
+				if err := json.Unmarshal(v, &event); err != nil {
+					return err
+				}
+				events = append(events, event)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return events, err
+}
+
+func webhookGet(db *badger.DB, key string) (*Webhook, error) {
+	var webhook *Webhook
+	err := db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return item.Value(func(v []byte) error {
+			webhook = &Webhook{}
+			return json.Unmarshal(v, webhook)
+		})
+	})
+
+	return webhook, err
+}
+
+func webhookSet(db *badger.DB, key string, value Webhook) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func webhookList(db *badger.DB, prefix string) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var webhook Webhook
+				if err := json.Unmarshal(v, &webhook); err != nil {
+					return err
+				}
+				webhooks = append(webhooks, webhook)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return webhooks, err
+}
+
+func webhookDeliverySet(db *badger.DB, key string, value WebhookDelivery) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func webhookDeliveryList(db *badger.DB, prefix string) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var delivery WebhookDelivery
+				if err := json.Unmarshal(v, &delivery); err != nil {
+					return err
+				}
+				deliveries = append(deliveries, delivery)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return deliveries, err
+}
+
+func registryEventSet(db *badger.DB, key string, value RegistryEvent) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(key), data)
+	})
+}
+
+func registryEventList(db *badger.DB, prefix string) ([]RegistryEvent, error) {
+	var events []RegistryEvent
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var event RegistryEvent
+				if err := json.Unmarshal(v, &event); err != nil {
+					return err
+				}
+				events = append(events, event)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return events, err
+}
+
+// downloadCountersIncrement adds one to every counter in a single transaction,
+// keys maps each counter key to the counter it stores.
+func downloadCountersIncrement(db *badger.DB, keys map[string]DownloadCounter) error {
+	return db.Update(func(txn *badger.Txn) error {
+		for key, counter := range keys {
+			item, err := txn.Get([]byte(key))
+			if err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+				return err
+			}
+			if err == nil {
+				var stored DownloadCounter
+				err = item.Value(func(v []byte) error {
+					return json.Unmarshal(v, &stored)
+				})
+				if err != nil {
+					return err
+				}
+				counter.Count = stored.Count
+			}
+			counter.Count++
+
+			data, err := json.Marshal(counter)
+			if err != nil {
+				return err
+			}
+			if err := txn.Set([]byte(key), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func downloadCounterList(db *badger.DB, prefix string) ([]DownloadCounter, error) {
+	var counters []DownloadCounter
+	err := db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			err := it.Item().Value(func(v []byte) error {
+				var counter DownloadCounter
+				if err := json.Unmarshal(v, &counter); err != nil {
+					return err
+				}
+				counters = append(counters, counter)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return counters, err
+}
+
+func keysWithPrefix(db *badger.DB, prefix string) ([]string, error) {
+	var keys []string
+	err := db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			keys = append(keys, string(it.Item().KeyCopy(nil)))
+		}
+		return nil
+	})
+
+	return keys, err
+}
+
+func keysDelete(db *badger.DB, keys []string) error {
+	return db.Update(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			if err := txn.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func duplicatePlatform(platforms []ProviderPlatform, os string, arch string) bool {
 	for _, platform := range platforms {
 		if strings.EqualFold(platform.OS, os) && strings.EqualFold(platform.Arch, arch) {
@@ -0,0 +1,425 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.OrganizationsBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) OrganizationsList(ctx context.Context) (*models.OrganizationsListResponse, error) {
+	var organizations []Organization
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		organizations, err = organizationList(db, b.Tables.OrganizationTableName+":")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.OrganizationsListResponse{
+		Data: []models.OrganizationsDataResponse{},
+	}
+
+	for _, organization := range organizations {
+		resp.Data = append(resp.Data, organizationResponse(organization).Data)
+	}
+
+	return resp, nil
+}
+
+func (b *BadgerDBBackend) OrganizationsCreate(ctx context.Context, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	organization := &Organization{
+		ID:              uuid.New().String(),
+		DefaultRegistry: "private",
+		CreatedAt:       time.Now().UTC(),
+	}
+	applyOrganizationAttributes(organization, request.Data.Attributes)
+
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		existing, err := organizationGet(db, b.organizationKey(organization.Name))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("organization already exists")
+		}
+
+		return organizationSet(db, b.organizationKey(organization.Name), *organization)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (b *BadgerDBBackend) OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.OrganizationsResponse, error) {
+	var organization *Organization
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		organization, err = organizationGet(db, b.organizationKey(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, nil
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (b *BadgerDBBackend) OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	var organization *Organization
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		organization, err = organizationGet(db, b.organizationKey(parameters.Organization))
+		if err != nil {
+			return err
+		}
+		if organization == nil {
+			return fmt.Errorf("organization not found")
+		}
+
+		name := organization.Name
+		applyOrganizationAttributes(organization, request.Data.Attributes)
+
+		if organization.Name != name {
+			err = b.organizationRename(db, name, organization.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		return organizationSet(db, b.organizationKey(organization.Name), *organization)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (b *BadgerDBBackend) OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	found := false
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		organization, err := organizationGet(db, b.organizationKey(parameters.Organization))
+		if err != nil || organization == nil {
+			return err
+		}
+		found = true
+
+		keys := []string{b.organizationKey(parameters.Organization)}
+
+		providerKeys, err := b.organizationKeys(db, b.Tables.ProviderTableName, parameters.Organization)
+		if err != nil {
+			return err
+		}
+		for _, key := range providerKeys {
+			var p Provider
+			if err := providerGet(db, key, &p); err != nil {
+				return err
+			}
+			versionKeys, err := keysWithPrefix(db, fmt.Sprintf("%s:%s:", b.Tables.ProviderVersionTableName, p.ID))
+			if err != nil {
+				return err
+			}
+			keys = append(keys, versionKeys...)
+		}
+		keys = append(keys, providerKeys...)
+
+		for _, table := range []string{b.Tables.NamespaceTableName, b.Tables.GPGTableName, b.Tables.DownloadTableName} {
+			tableKeys, err := b.organizationKeys(db, table, parameters.Organization)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, tableKeys...)
+		}
+
+		shares, err := shareList(db, b.Tables.ShareTableName+":")
+		if err != nil {
+			return err
+		}
+		for _, share := range shares {
+			if strings.EqualFold(share.Organization, parameters.Organization) || strings.EqualFold(share.Grantee, parameters.Organization) {
+				keys = append(keys, b.sharePrefix(share.Organization)+share.ID)
+			}
+		}
+
+		webhooks, err := webhookList(db, b.webhookPrefix(parameters.Organization))
+		if err != nil {
+			return err
+		}
+		for _, webhook := range webhooks {
+			deliveryKeys, err := keysWithPrefix(db, b.webhookDeliveryPrefix(webhook.ID))
+			if err != nil {
+				return err
+			}
+			keys = append(keys, deliveryKeys...)
+			keys = append(keys, b.webhookPrefix(parameters.Organization)+webhook.ID)
+		}
+
+		eventKeys, err := keysWithPrefix(db, b.eventPrefix(parameters.Organization))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, eventKeys...)
+
+		return keysDelete(db, keys)
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !found {
+		return http.StatusNotFound, fmt.Errorf("organization not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+// organizationsBackfill creates the records of organizations that are only
+// referenced by providers, modules, namespaces or shares written before
+// organizations were stored, the equivalent of the Postgres migration.
+func (b *BadgerDBBackend) organizationsBackfill(ctx context.Context) error {
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var names []string
+		seen := make(map[string]bool)
+
+		tables := []string{b.Tables.ProviderTableName, b.Tables.ModuleTableName, b.Tables.NamespaceTableName, b.Tables.ShareTableName}
+		for _, table := range tables {
+			keys, err := keysWithPrefix(db, table+":")
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				parts := strings.SplitN(key, ":", 3)
+				if len(parts) != 3 || parts[1] == "" || seen[strings.ToLower(parts[1])] {
+					continue
+				}
+				seen[strings.ToLower(parts[1])] = true
+				names = append(names, parts[1])
+			}
+		}
+
+		for _, name := range names {
+			existing, err := organizationGet(db, b.organizationKey(name))
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				continue
+			}
+
+			organization := Organization{
+				ID:              uuid.New().String(),
+				Name:            name,
+				DefaultRegistry: "private",
+				CreatedAt:       time.Now().UTC(),
+			}
+			err = organizationSet(db, b.organizationKey(name), organization)
+			if err != nil {
+				return err
+			}
+
+			slog.InfoContext(ctx, "Created organization for existing data", "organization", name)
+		}
+
+		return nil
+	})
+}
+
+// organizationRename moves namespaces, shares, GPG keys and webhooks to the
+// new name and drops the event log and download counters, which only outlive
+// deleted providers. Providers are refused since their storage paths and
+// registry addresses contain the organization name.
+func (b *BadgerDBBackend) organizationRename(db *badger.DB, name string, newName string) error {
+	existing, err := organizationGet(db, b.organizationKey(newName))
+	if err != nil {
+		return err
+	}
+	if existing != nil && !strings.EqualFold(name, newName) {
+		return fmt.Errorf("organization already exists")
+	}
+
+	providerKeys, err := b.organizationKeys(db, b.Tables.ProviderTableName, name)
+	if err != nil {
+		return err
+	}
+	if len(providerKeys) > 0 {
+		return fmt.Errorf("organization with providers or modules cannot be renamed")
+	}
+
+	var obsolete []string
+	written := map[string]bool{
+		b.organizationKey(newName): true,
+	}
+
+	namespaceKeys, err := b.organizationKeys(db, b.Tables.NamespaceTableName, name)
+	if err != nil {
+		return err
+	}
+	for _, key := range namespaceKeys {
+		var ns Namespace
+		if err := namespaceGet(db, key, &ns); err != nil {
+			return err
+		}
+		ns.Organization = newName
+		if strings.EqualFold(ns.Namespace, name) {
+			ns.Namespace = newName
+		}
+		newKey := b.namespaceKey(registrytypes.APIParameters{Organization: ns.Organization, Namespace: ns.Namespace})
+		if err := namespaceSet(db, newKey, ns); err != nil {
+			return err
+		}
+		written[newKey] = true
+		obsolete = append(obsolete, key)
+	}
+
+	gpgKeys, err := b.organizationKeys(db, b.Tables.GPGTableName, name)
+	if err != nil {
+		return err
+	}
+	for _, key := range gpgKeys {
+		var gpg GPGKey
+		if err := gpgGet(db, key, &gpg); err != nil {
+			return err
+		}
+		gpg.Namespace = newName
+		newKey := fmt.Sprintf("%s:%s:%s", b.Tables.GPGTableName, newName, gpg.KeyID)
+		if err := gpgSet(db, newKey, gpg); err != nil {
+			return err
+		}
+		written[newKey] = true
+		obsolete = append(obsolete, key)
+	}
+
+	shares, err := shareList(db, b.Tables.ShareTableName+":")
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		if !strings.EqualFold(share.Organization, name) && !strings.EqualFold(share.Grantee, name) {
+			continue
+		}
+
+		key := b.sharePrefix(share.Organization) + share.ID
+		if strings.EqualFold(share.Grantee, name) {
+			share.Grantee = newName
+		}
+		if strings.EqualFold(share.Organization, name) {
+			share.Organization = newName
+			if strings.EqualFold(share.Namespace, name) {
+				share.Namespace = newName
+			}
+		}
+		newKey := b.sharePrefix(share.Organization) + share.ID
+		if err := shareSet(db, newKey, share); err != nil {
+			return err
+		}
+		written[newKey] = true
+		obsolete = append(obsolete, key)
+	}
+
+	webhooks, err := webhookList(db, b.webhookPrefix(name))
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		key := b.webhookPrefix(name) + webhook.ID
+		webhook.Organization = newName
+		newKey := b.webhookPrefix(newName) + webhook.ID
+		if err := webhookSet(db, newKey, webhook); err != nil {
+			return err
+		}
+		written[newKey] = true
+		obsolete = append(obsolete, key)
+	}
+
+	eventKeys, err := keysWithPrefix(db, b.eventPrefix(name))
+	if err != nil {
+		return err
+	}
+	obsolete = append(obsolete, eventKeys...)
+
+	downloadKeys, err := b.organizationKeys(db, b.Tables.DownloadTableName, name)
+	if err != nil {
+		return err
+	}
+	obsolete = append(obsolete, downloadKeys...)
+
+	obsolete = append(obsolete, b.organizationKey(name))
+
+	// Names only differing in case map to the same keys after the rename
+	var stale []string
+	for _, key := range obsolete {
+		if !written[key] {
+			stale = append(stale, key)
+		}
+	}
+
+	return keysDelete(db, stale)
+}
+
+// organizationKeys returns the keys in table whose organization segment
+// matches, ignoring case since older keys kept the case of the request.
+func (b *BadgerDBBackend) organizationKeys(db *badger.DB, table string, organization string) ([]string, error) {
+	keys, err := keysWithPrefix(db, table+":")
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, key := range keys {
+		parts := strings.SplitN(key, ":", 3)
+		if len(parts) == 3 && strings.EqualFold(parts[1], organization) {
+			matched = append(matched, key)
+		}
+	}
+
+	return matched, nil
+}
+
+func (b *BadgerDBBackend) organizationKey(name string) string {
+	return fmt.Sprintf("%s:%s", b.Tables.OrganizationTableName, strings.ToLower(name))
+}
+
+func applyOrganizationAttributes(organization *Organization, attributes models.OrganizationsAttributesRequest) {
+	if attributes.Name != nil {
+		organization.Name = *attributes.Name
+	}
+	if attributes.Email != nil {
+		organization.Email = *attributes.Email
+	}
+	if attributes.DefaultRegistry != nil {
+		organization.DefaultRegistry = *attributes.DefaultRegistry
+	}
+}
+
+func organizationResponse(organization Organization) *models.OrganizationsResponse {
+	return &models.OrganizationsResponse{
+		Data: models.OrganizationsDataResponse{
+			ID:   organization.Name,
+			Type: "organizations",
+			Attributes: models.OrganizationsAttributesResponse{
+				Name:            organization.Name,
+				Email:           organization.Email,
+				DefaultRegistry: organization.DefaultRegistry,
+				ExternalID:      organization.ID,
+				CreatedAt:       organization.CreatedAt.Format(time.RFC3339),
+			},
+		},
+	}
+}
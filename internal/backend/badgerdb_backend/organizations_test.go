@@ -0,0 +1,70 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"github.com/dgraph-io/badger/v4"
+	registrytypes "go-terraform-registry/internal/types"
+	"testing"
+)
+
+func TestConfigureBackfillsOrganizations(t *testing.T) {
+	ctx := context.Background()
+
+	b := &BadgerDBBackend{}
+	b.Config.BadgerDB.Path = t.TempDir()
+
+	// A store written before organizations were recorded
+	err := withBadgerDB(ctx, b.Config.BadgerDB.Path, func(db *badger.DB) error {
+		if err := providerSet(db, "providers:Acme:private:acme/aws", Provider{ID: "provider"}); err != nil {
+			return err
+		}
+		if err := namespaceSet(db, "namespaces:acme:acme", Namespace{Organization: "acme", Namespace: "acme"}); err != nil {
+			return err
+		}
+		if err := namespaceSet(db, "namespaces:globex:globex", Namespace{Organization: "globex", Namespace: "globex"}); err != nil {
+			return err
+		}
+		if err := shareSet(db, "shares:initech:share", Share{ID: "share", Organization: "initech", Grantee: "acme"}); err != nil {
+			return err
+		}
+		return organizationSet(db, "organizations:globex", Organization{ID: "existing", Name: "Globex", Email: "ops@globex.example"})
+	})
+	if err != nil {
+		t.Fatalf("seeding store: %v", err)
+	}
+
+	if err := b.Configure(ctx); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	// Restarting must not create the organizations again
+	if err := b.Configure(ctx); err != nil {
+		t.Fatalf("second Configure() error = %v", err)
+	}
+
+	list, err := b.OrganizationsList(ctx)
+	if err != nil {
+		t.Fatalf("OrganizationsList() error = %v", err)
+	}
+	if len(list.Data) != 3 {
+		t.Errorf("OrganizationsList() returned %d organizations, want 3", len(list.Data))
+	}
+
+	for name, want := range map[string]string{"acme": "Acme", "initech": "initech", "globex": "Globex"} {
+		organization, err := b.OrganizationsGet(ctx, registrytypes.APIParameters{Organization: name})
+		if err != nil {
+			t.Fatalf("OrganizationsGet(%s) error = %v", name, err)
+		}
+		if organization == nil {
+			t.Errorf("organization %s was not created", name)
+			continue
+		}
+		if organization.Data.Attributes.Name != want {
+			t.Errorf("organization %s name = %q, want %q", name, organization.Data.Attributes.Name, want)
+		}
+	}
+
+	globex, _ := b.OrganizationsGet(ctx, registrytypes.APIParameters{Organization: "globex"})
+	if globex != nil && globex.Data.Attributes.Email != "ops@globex.example" {
+		t.Errorf("existing organization was replaced: %+v", globex.Data)
+	}
+}
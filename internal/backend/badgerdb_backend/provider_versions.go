@@ -2,6 +2,7 @@ package badgerdb_backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/dgraph-io/badger/v4"
 	"github.com/google/uuid"
@@ -20,7 +21,7 @@ func (b *BadgerDBBackend) ProviderVersionsCreate(ctx context.Context, parameters
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
@@ -29,16 +30,19 @@ func (b *BadgerDBBackend) ProviderVersionsCreate(ctx context.Context, parameters
 
 	var gpg GPGKey
 	gpgKey := fmt.Sprintf("%s:%s:%s", b.Tables.GPGTableName, parameters.Namespace, request.Data.Attributes.KeyID)
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return gpgGet(db, gpgKey, &gpg)
 	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, backend.ErrSigningKeyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	var pv ProviderVersion
 	pvKey := fmt.Sprintf("%s:%s:%s", b.Tables.ProviderVersionTableName, p.ID, request.Data.Attributes.Version)
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		err := providerVersionGet(db, pvKey, &pv)
 		if err != nil {
 			return err
@@ -80,7 +84,7 @@ func (b *BadgerDBBackend) ProviderVersionsGet(ctx context.Context, parameters re
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
@@ -89,7 +93,7 @@ func (b *BadgerDBBackend) ProviderVersionsGet(ctx context.Context, parameters re
 
 	pvKey := fmt.Sprintf("%s:%s:%s", b.Tables.ProviderVersionTableName, p.ID, parameters.Version)
 	var pv ProviderVersion
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerVersionGet(db, pvKey, &pv)
 	})
 	if err != nil {
@@ -115,7 +119,7 @@ func (b *BadgerDBBackend) ProviderVersionPlatformsCreate(ctx context.Context, pa
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
@@ -124,7 +128,7 @@ func (b *BadgerDBBackend) ProviderVersionPlatformsCreate(ctx context.Context, pa
 
 	pvKey := fmt.Sprintf("%s:%s:%s", b.Tables.ProviderVersionTableName, p.ID, parameters.Version)
 	var pv ProviderVersion
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerVersionGet(db, pvKey, &pv)
 	})
 	if err != nil {
@@ -146,7 +150,7 @@ func (b *BadgerDBBackend) ProviderVersionPlatformsCreate(ctx context.Context, pa
 	}
 
 	pv.Platform = append(pv.Platform, platform)
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerVersionSet(db, pvKey, pv)
 	})
 	if err != nil {
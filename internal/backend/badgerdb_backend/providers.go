@@ -15,7 +15,7 @@ var _ backend.ProvidersBackend = &BadgerDBBackend{}
 func (b *BadgerDBBackend) ProvidersCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.ProvidersRequest) (*models.ProvidersResponse, error) {
 	var p Provider
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, parameters.Organization, request.Data.Attributes.RegistryName, request.Data.Attributes.Namespace, request.Data.Attributes.Name)
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		err := providerGet(db, key, &p)
 		if err != nil && err.Error() != "provider not found" {
 			return err
@@ -54,7 +54,7 @@ func (b *BadgerDBBackend) ProvidersGet(ctx context.Context, parameters registryt
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, parameters.Organization, parameters.Registry, parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
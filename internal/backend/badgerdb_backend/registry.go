@@ -17,7 +17,7 @@ func (b *BadgerDBBackend) GetProvider(ctx context.Context, parameters registryty
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, userParameters.Organization, "private", parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
@@ -27,7 +27,7 @@ func (b *BadgerDBBackend) GetProvider(ctx context.Context, parameters registryty
 	filter := fmt.Sprintf("%s:%s:%s", b.Tables.ProviderVersionTableName, p.ID, parameters.Version)
 
 	var pv ProviderVersion
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerVersionGet(db, filter, &pv)
 	})
 	if err != nil {
@@ -65,7 +65,7 @@ func (b *BadgerDBBackend) GetProviderVersions(ctx context.Context, parameters re
 	key := fmt.Sprintf("%s:%s:%s:%s/%s", b.Tables.ProviderTableName, userParameters.Organization, "private", parameters.Namespace, parameters.Name)
 
 	var p Provider
-	err := withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return providerGet(db, key, &p)
 	})
 	if err != nil {
@@ -76,7 +76,7 @@ func (b *BadgerDBBackend) GetProviderVersions(ctx context.Context, parameters re
 	prefix := []byte(filter + ":") // Prefix for filtering
 
 	var providerVersions []ProviderVersion
-	err = withBadgerDB(b.DBPath, func(db *badger.DB) error {
+	err = withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
 		return db.View(func(txn *badger.Txn) error {
 			opts := badger.DefaultIteratorOptions
 			opts.PrefetchSize = 10
@@ -0,0 +1,152 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.SharesBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*models.SharesListResponse, error) {
+	var shares []Share
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		shares, err = shareList(db, b.sharePrefix(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.SharesListResponse{
+		Data: []models.SharesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.PaginationMeta{
+				PageSize:    len(shares),
+				CurrentPage: 1,
+				TotalPages:  1,
+				TotalCount:  len(shares),
+			},
+		},
+	}
+
+	for _, share := range shares {
+		resp.Data = append(resp.Data, shareData(share))
+	}
+
+	return resp, nil
+}
+
+func (b *BadgerDBBackend) SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.SharesRequest) (*models.SharesResponse, error) {
+	share := Share{
+		ID:           uuid.New().String(),
+		Organization: parameters.Organization,
+		Grantee:      request.Data.Attributes.GranteeOrganization,
+		ResourceType: request.Data.Attributes.ResourceType,
+		Namespace:    request.Data.Attributes.Namespace,
+		Name:         request.Data.Attributes.Name,
+		Provider:     request.Data.Attributes.Provider,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		existing, err := shareList(db, b.sharePrefix(parameters.Organization))
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			if sameShare(e, share) {
+				return fmt.Errorf("share already exists")
+			}
+		}
+
+		return shareSet(db, b.sharePrefix(parameters.Organization)+share.ID, share)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharesResponse{
+		Data: shareData(share),
+	}, nil
+}
+
+func (b *BadgerDBBackend) SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error) {
+	var deleted bool
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		deleted, err = shareDelete(db, b.sharePrefix(parameters.Organization)+shareID)
+		return err
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, fmt.Errorf("share not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (b *BadgerDBBackend) SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error) {
+	var shares []Share
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		shares, err = shareList(db, b.sharePrefix(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, share := range shares {
+		if share.ResourceType == parameters.ResourceType &&
+			strings.EqualFold(share.Namespace, parameters.Namespace) &&
+			strings.EqualFold(share.Name, parameters.Name) &&
+			strings.EqualFold(share.Provider, parameters.Provider) {
+			for _, grantee := range parameters.Grantees {
+				if strings.EqualFold(share.Grantee, grantee) {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (b *BadgerDBBackend) sharePrefix(organization string) string {
+	return fmt.Sprintf("%s:%s:", b.Tables.ShareTableName, strings.ToLower(organization))
+}
+
+func sameShare(a Share, b Share) bool {
+	return strings.EqualFold(a.Grantee, b.Grantee) &&
+		a.ResourceType == b.ResourceType &&
+		strings.EqualFold(a.Namespace, b.Namespace) &&
+		strings.EqualFold(a.Name, b.Name) &&
+		strings.EqualFold(a.Provider, b.Provider)
+}
+
+func shareData(share Share) models.SharesDataResponse {
+	return models.SharesDataResponse{
+		ID:   share.ID,
+		Type: "registry-shares",
+		Attributes: models.SharesAttributesResponse{
+			Organization:        share.Organization,
+			GranteeOrganization: share.Grantee,
+			ResourceType:        share.ResourceType,
+			Namespace:           share.Namespace,
+			Name:                share.Name,
+			Provider:            share.Provider,
+			CreatedAt:           share.CreatedAt.Format(time.RFC3339),
+		},
+	}
+}
@@ -0,0 +1,277 @@
+package badgerdb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgraph-io/badger/v4"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.WebhooksBackend = &BadgerDBBackend{}
+
+func (b *BadgerDBBackend) WebhooksList(ctx context.Context, parameters registrytypes.APIParameters) (*models.WebhooksListResponse, error) {
+	var webhooks []Webhook
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		webhooks, err = webhookList(db, b.webhookPrefix(parameters.Organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhooksListResponse{
+		Data: []models.WebhooksDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(1, len(webhooks), len(webhooks)),
+		},
+	}
+
+	for _, webhook := range webhooks {
+		resp.Data = append(resp.Data, webhookData(webhook))
+	}
+
+	return resp, nil
+}
+
+func (b *BadgerDBBackend) WebhooksCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	now := time.Now().UTC()
+	webhook := Webhook{
+		ID:           uuid.New().String(),
+		Organization: parameters.Organization,
+		Events:       []string{},
+		Enabled:      true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	applyWebhookAttributes(&webhook, request.Data.Attributes)
+
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		return webhookSet(db, b.webhookPrefix(parameters.Organization)+webhook.ID, webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(webhook),
+	}, nil
+}
+
+func (b *BadgerDBBackend) WebhooksGet(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (*models.WebhooksResponse, error) {
+	var webhook *Webhook
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		webhook, err = webhookGet(db, b.webhookPrefix(parameters.Organization)+webhookID)
+		return err
+	})
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (b *BadgerDBBackend) WebhooksUpdate(ctx context.Context, parameters registrytypes.APIParameters, webhookID string, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	var webhook *Webhook
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		key := b.webhookPrefix(parameters.Organization) + webhookID
+		webhook, err = webhookGet(db, key)
+		if err != nil || webhook == nil {
+			return err
+		}
+
+		applyWebhookAttributes(webhook, request.Data.Attributes)
+		webhook.UpdatedAt = time.Now().UTC()
+
+		return webhookSet(db, key, *webhook)
+	})
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (b *BadgerDBBackend) WebhooksDelete(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (int, error) {
+	found := false
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		key := b.webhookPrefix(parameters.Organization) + webhookID
+		webhook, err := webhookGet(db, key)
+		if err != nil || webhook == nil {
+			return err
+		}
+		found = true
+
+		keys, err := keysWithPrefix(db, b.webhookDeliveryPrefix(webhookID))
+		if err != nil {
+			return err
+		}
+
+		return keysDelete(db, append(keys, key))
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !found {
+		return http.StatusNotFound, fmt.Errorf("webhook not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (b *BadgerDBBackend) WebhooksSubscribed(ctx context.Context, organization string) ([]registrytypes.Webhook, error) {
+	var webhooks []Webhook
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		webhooks, err = webhookList(db, b.webhookPrefix(organization))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []registrytypes.Webhook
+	for _, webhook := range webhooks {
+		if !webhook.Enabled {
+			continue
+		}
+
+		subscribed = append(subscribed, registrytypes.Webhook{
+			ID:           webhook.ID,
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Secret:       webhook.Secret,
+			Enabled:      webhook.Enabled,
+		})
+	}
+
+	return subscribed, nil
+}
+
+func (b *BadgerDBBackend) WebhookDeliveriesCreate(ctx context.Context, delivery registrytypes.WebhookDelivery) error {
+	value := WebhookDelivery{
+		ID:         uuid.New().String(),
+		WebhookID:  delivery.WebhookID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Error:      delivery.Error,
+		DurationMS: delivery.Duration.Milliseconds(),
+		Success:    delivery.Success,
+		CreatedAt:  delivery.Timestamp.UTC(),
+	}
+
+	// Keys sort by time so the oldest attempts are pruned first
+	prefix := b.webhookDeliveryPrefix(delivery.WebhookID)
+	key := fmt.Sprintf("%s%020d:%s", prefix, value.CreatedAt.UnixNano(), value.ID)
+
+	return withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		err := webhookDeliverySet(db, key, value)
+		if err != nil {
+			return err
+		}
+
+		keys, err := keysWithPrefix(db, prefix)
+		if err != nil || len(keys) <= backend.WebhookDeliveryRetention {
+			return err
+		}
+
+		return keysDelete(db, keys[:len(keys)-backend.WebhookDeliveryRetention])
+	})
+}
+
+func (b *BadgerDBBackend) WebhookDeliveriesList(ctx context.Context, parameters registrytypes.WebhookDeliveryParameters) (*models.WebhookDeliveriesListResponse, error) {
+	var deliveries []WebhookDelivery
+	err := withBadgerDB(ctx, b.DBPath, func(db *badger.DB) error {
+		var err error
+		deliveries, err = webhookDeliveryList(db, b.webhookDeliveryPrefix(parameters.WebhookID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhookDeliveriesListResponse{
+		Data: []models.WebhookDeliveriesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, len(deliveries)),
+		},
+	}
+
+	// Newest first
+	start := (parameters.PageNumber - 1) * parameters.PageSize
+	for i := start; i < len(deliveries) && i < start+parameters.PageSize; i++ {
+		resp.Data = append(resp.Data, webhookDeliveryData(deliveries[len(deliveries)-1-i]))
+	}
+
+	return resp, nil
+}
+
+func (b *BadgerDBBackend) webhookPrefix(organization string) string {
+	return fmt.Sprintf("%s:%s:", b.Tables.WebhookTableName, strings.ToLower(organization))
+}
+
+func (b *BadgerDBBackend) webhookDeliveryPrefix(webhookID string) string {
+	return fmt.Sprintf("%s:%s:", b.Tables.WebhookDeliveryTableName, webhookID)
+}
+
+func applyWebhookAttributes(webhook *Webhook, attributes models.WebhooksAttributesRequest) {
+	if attributes.URL != nil {
+		webhook.URL = *attributes.URL
+	}
+	if attributes.Events != nil {
+		webhook.Events = *attributes.Events
+	}
+	if attributes.Secret != nil {
+		webhook.Secret = *attributes.Secret
+	}
+	if attributes.Enabled != nil {
+		webhook.Enabled = *attributes.Enabled
+	}
+}
+
+func webhookData(webhook Webhook) models.WebhooksDataResponse {
+	return models.WebhooksDataResponse{
+		ID:   webhook.ID,
+		Type: "webhooks",
+		Attributes: models.WebhooksAttributesResponse{
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Enabled:      webhook.Enabled,
+			CreatedAt:    webhook.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    webhook.UpdatedAt.Format(time.RFC3339),
+		},
+	}
+}
+
+func webhookDeliveryData(delivery WebhookDelivery) models.WebhookDeliveriesDataResponse {
+	return models.WebhookDeliveriesDataResponse{
+		ID:   delivery.ID,
+		Type: "webhook-deliveries",
+		Attributes: models.WebhookDeliveriesAttributesResponse{
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			DurationMS: delivery.DurationMS,
+			Success:    delivery.Success,
+			CreatedAt:  delivery.CreatedAt.Format(time.RFC3339Nano),
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// auditSortFormat has a fixed width so sort keys order chronologically
+	auditSortFormat = "2006-01-02T15:04:05.000000000Z"
+	// auditHeadEvent sorts before every timestamp, outside the listed range
+	auditHeadEvent = "#head"
+	// auditAppendAttempts bounds the retries when writers race for the head
+	auditAppendAttempts = 5
+)
+
+var _ backend.AuditBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) AuditEventsCreate(ctx context.Context, event registrytypes.AuditEvent) error {
+	id := uuid.New().String()
+	timestamp := event.Timestamp.UTC()
+
+	value := AuditEvent{
+		Organization: strings.ToLower(event.Organization),
+		Event:        timestamp.Format(auditSortFormat) + "#" + id,
+		ID:           id,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		CreatedAt:    timestamp.Format(time.RFC3339Nano),
+	}
+
+	for attempt := 0; attempt < auditAppendAttempts; attempt++ {
+		headSequence, headHash, err := getAuditHead(ctx, d.client, d.Tables.AuditTableName, value.Organization)
+		if err != nil {
+			return err
+		}
+
+		linkAuditEvent(&value, headSequence, headHash)
+
+		err = appendAuditEvent(ctx, d.client, d.Tables.AuditTableName, headSequence, value)
+		var cancelled *types.TransactionCanceledException
+		if !errors.As(err, &cancelled) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("audit chain head of %s is contended", value.Organization)
+}
+
+func (d *DynamoDBBackend) AuditEventsExport(ctx context.Context, parameters registrytypes.AuditParameters) ([]chain.Record, error) {
+	events, err := listAuditEvents(ctx, d.client, d.Tables.AuditTableName, strings.ToLower(parameters.Organization), "0", "9")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Sequence < events[j].Sequence
+	})
+
+	var records []chain.Record
+	for _, event := range events {
+		if event.Sequence == 0 {
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RFC3339Nano, event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if parameters.Since != nil && createdAt.Before(*parameters.Since) {
+			continue
+		}
+		if parameters.Until != nil && !createdAt.Before(*parameters.Until) {
+			continue
+		}
+
+		records = append(records, auditRecord(event))
+	}
+
+	return records, nil
+}
+
+func (d *DynamoDBBackend) AuditEventsList(ctx context.Context, parameters registrytypes.AuditParameters) (*models.AuditEventsListResponse, error) {
+	from := "0"
+	if parameters.Since != nil {
+		from = parameters.Since.UTC().Format(auditSortFormat)
+	}
+	to := "9"
+	if parameters.Until != nil {
+		// "#" sorts before the id so events at exactly until are excluded
+		to = parameters.Until.UTC().Format(auditSortFormat)
+	}
+
+	events, err := listAuditEvents(ctx, d.client, d.Tables.AuditTableName, strings.ToLower(parameters.Organization), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.AuditEventsListResponse{
+		Data: []models.AuditEventsDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, len(events)),
+		},
+	}
+
+	start := (parameters.PageNumber - 1) * parameters.PageSize
+	for i := start; i < len(events) && i < start+parameters.PageSize; i++ {
+		resp.Data = append(resp.Data, auditEventData(events[i]))
+	}
+
+	return resp, nil
+}
+
+func linkAuditEvent(event *AuditEvent, headSequence int64, headHash string) {
+	record := auditRecord(*event)
+	chain.Link(&record, headSequence, headHash)
+
+	event.Sequence = record.Sequence
+	event.PrevHash = record.PrevHash
+	event.Hash = record.Hash
+}
+
+func auditRecord(event AuditEvent) chain.Record {
+	return chain.Record{
+		Sequence:     event.Sequence,
+		ID:           event.ID,
+		Timestamp:    event.CreatedAt,
+		Organization: event.Organization,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		PrevHash:     event.PrevHash,
+		Hash:         event.Hash,
+	}
+}
+
+func auditEventData(event AuditEvent) models.AuditEventsDataResponse {
+	return models.AuditEventsDataResponse{
+		ID:   event.ID,
+		Type: "audit-events",
+		Attributes: models.AuditEventsAttributesResponse{
+			Timestamp:    event.CreatedAt,
+			Organization: event.Organization,
+			Actor:        event.Actor,
+			Action:       event.Action,
+			Target:       event.Target,
+			SourceIP:     event.SourceIP,
+			Outcome:      event.Outcome,
+			StatusCode:   event.StatusCode,
+		},
+	}
+}
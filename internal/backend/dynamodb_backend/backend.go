@@ -7,10 +7,11 @@ import (
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"go-terraform-registry/internal/backend"
 	"go-terraform-registry/internal/config"
-	"log"
+	"log/slog"
 )
 
 var _ backend.BackendLifecycle = &DynamoDBBackend{}
@@ -27,6 +28,14 @@ type DynamoTables struct {
 	ProviderTableName        string
 	ProviderVersionTableName string
 	ModuleTableName          string
+	NamespaceTableName       string
+	ShareTableName           string
+	OrganizationTableName    string
+	AuditTableName           string
+	WebhookTableName         string
+	WebhookDeliveryTableName string
+	EventTableName           string
+	DownloadTableName        string
 }
 
 func NewDynamoDBBackend(ctx context.Context, config config.RegistryConfig) (*backend.Backend, error) {
@@ -35,12 +44,20 @@ func NewDynamoDBBackend(ctx context.Context, config config.RegistryConfig) (*bac
 	}
 
 	return &backend.Backend{
+		BackendLifecycle:        b,
 		RegistryBackend:         b,
 		ProvidersBackend:        b,
 		ProviderVersionsBackend: b,
 		ModulesBackend:          b,
 		ModuleVersionsBackend:   b,
 		GPGKeysBackend:          b,
+		NamespacesBackend:       b,
+		SharesBackend:           b,
+		OrganizationsBackend:    b,
+		AuditBackend:            b,
+		WebhooksBackend:         b,
+		EventsBackend:           b,
+		DownloadsBackend:        b,
 	}, nil
 }
 
@@ -49,21 +66,34 @@ func (d *DynamoDBBackend) Configure(ctx context.Context) error {
 	d.Tables.ProviderTableName = "terraform_providers"
 	d.Tables.ProviderVersionTableName = "terraform_providers_versions"
 	d.Tables.ModuleTableName = "terraform_modules"
+	d.Tables.NamespaceTableName = "terraform_namespaces"
+	d.Tables.ShareTableName = "terraform_shares"
+	d.Tables.OrganizationTableName = "terraform_organizations"
+	d.Tables.AuditTableName = "terraform_audit_events"
+	d.Tables.WebhookTableName = "terraform_webhooks"
+	d.Tables.WebhookDeliveryTableName = "terraform_webhook_deliveries"
+	d.Tables.EventTableName = "terraform_events"
+	d.Tables.DownloadTableName = "terraform_download_counters"
 
 	cfg, err := awsconfig.LoadDefaultConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
-	if d.Config.AssumeRoleARN != "" {
+	if d.Config.DynamoDB.AssumeRoleARN != "" {
 		stsClient := sts.NewFromConfig(cfg)
-		credentials := stscreds.NewAssumeRoleProvider(stsClient, d.Config.AssumeRoleARN)
+		credentials := stscreds.NewAssumeRoleProvider(stsClient, d.Config.DynamoDB.AssumeRoleARN)
 		cfg.Credentials = aws.NewCredentialsCache(credentials)
 	}
 
 	d.client = dynamodb.NewFromConfig(cfg)
 
-	log.Println("Using DynamoDB for backend.")
+	slog.InfoContext(ctx, "Using DynamoDB backend")
+
+	err = d.organizationsBackfill(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating organizations for existing data: %w", err)
+	}
 
 	return nil
 }
@@ -71,3 +101,39 @@ func (d *DynamoDBBackend) Configure(ctx context.Context) error {
 func (d *DynamoDBBackend) Close(ctx context.Context) error {
 	return nil
 }
+
+// HealthCheck describes every table, a table that is missing or being deleted
+// fails the check.
+func (d *DynamoDBBackend) HealthCheck(ctx context.Context) error {
+	tables := []string{
+		d.Tables.GPGTableName,
+		d.Tables.ProviderTableName,
+		d.Tables.ProviderVersionTableName,
+		d.Tables.ModuleTableName,
+		d.Tables.NamespaceTableName,
+		d.Tables.ShareTableName,
+		d.Tables.OrganizationTableName,
+		d.Tables.AuditTableName,
+		d.Tables.WebhookTableName,
+		d.Tables.WebhookDeliveryTableName,
+		d.Tables.EventTableName,
+		d.Tables.DownloadTableName,
+	}
+
+	for _, table := range tables {
+		out, err := d.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(table),
+		})
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+
+		switch out.Table.TableStatus {
+		case types.TableStatusActive, types.TableStatusUpdating:
+		default:
+			return fmt.Errorf("table %s is %s", table, out.Table.TableStatus)
+		}
+	}
+
+	return nil
+}
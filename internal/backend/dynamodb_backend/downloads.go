@@ -0,0 +1,82 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"strings"
+)
+
+var _ backend.DownloadsBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) DownloadsRecord(ctx context.Context, download registrytypes.Download) error {
+	subject := downloadSubject(download.Kind, download.Namespace, download.Name, download.Provider)
+
+	for _, counter := range backend.DownloadCounters(download) {
+		err := incrementDownloadCounter(ctx, d.client, d.Tables.DownloadTableName, DownloadCounter{
+			Subject:   subject,
+			Counter:   fmt.Sprintf("%s#%s#%s", counter.Version, counter.Dimension, counter.Key),
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DynamoDBBackend) DownloadsList(ctx context.Context, parameters registrytypes.DownloadParameters) ([]registrytypes.DownloadCounter, error) {
+	subject := downloadSubject(parameters.Kind, parameters.Namespace, parameters.Name, parameters.Provider)
+	counters, err := listDownloadCounters(ctx, d.client, d.Tables.DownloadTableName, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []registrytypes.DownloadCounter
+	for _, counter := range counters {
+		result = append(result, registrytypes.DownloadCounter{
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+			Count:     counter.Count,
+		})
+	}
+
+	return result, nil
+}
+
+// deleteDownloadCounters removes the counters of every provider and module in
+// a namespace.
+func (d *DynamoDBBackend) deleteDownloadCounters(ctx context.Context, namespace string) error {
+	items, err := scanItems(ctx, d.client, d.Tables.DownloadTableName)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.ToLower(namespace) + "#"
+	for _, item := range items {
+		counter := downloadCounterItem(item)
+		if !strings.HasPrefix(counter.Subject, prefix) {
+			continue
+		}
+
+		err = deleteItem(ctx, d.client, d.Tables.DownloadTableName, map[string]types.AttributeValue{
+			"subject": &types.AttributeValueMemberS{Value: counter.Subject},
+			"counter": &types.AttributeValueMemberS{Value: counter.Counter},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downloadSubject(kind string, namespace string, name string, provider string) string {
+	return strings.ToLower(fmt.Sprintf("%s#%s#%s#%s", namespace, kind, name, provider))
+}
@@ -0,0 +1,97 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/events"
+	"strings"
+	"time"
+)
+
+var _ backend.EventsBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) EventsCreate(ctx context.Context, event events.Event) error {
+	organization := strings.ToLower(event.Organization)
+
+	// Sort keys use the insertion time so the log keeps publish order
+	value := RegistryEvent{
+		Organization: organization,
+		Event:        time.Now().UTC().Format(auditSortFormat) + "#" + event.ID,
+		ID:           event.ID,
+		Type:         event.Type,
+		Subject:      event.Subject,
+		Data:         event.Data,
+		CreatedAt:    event.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+
+	err := setRegistryEvent(ctx, d.client, d.Tables.EventTableName, value)
+	if err != nil {
+		return err
+	}
+
+	logged, err := listRegistryEvents(ctx, d.client, d.Tables.EventTableName, organization)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(logged)-backend.EventLogRetention; i++ {
+		err = d.deleteRegistryEvent(ctx, logged[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DynamoDBBackend) EventsList(ctx context.Context, organization string, lastEventID string) ([]events.Event, error) {
+	logged, err := listRegistryEvents(ctx, d.client, d.Tables.EventTableName, strings.ToLower(organization))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, event := range logged {
+		if event.ID == lastEventID {
+			logged = logged[i+1:]
+			break
+		}
+	}
+
+	var result []events.Event
+	for _, event := range logged {
+		timestamp, _ := time.Parse(time.RFC3339Nano, event.CreatedAt)
+		result = append(result, events.Event{
+			ID:           event.ID,
+			Type:         event.Type,
+			Organization: event.Organization,
+			Subject:      event.Subject,
+			Timestamp:    timestamp,
+			Data:         event.Data,
+		})
+	}
+
+	return result, nil
+}
+
+// deleteEventLog removes the event log of an organization.
+func (d *DynamoDBBackend) deleteEventLog(ctx context.Context, organization string) error {
+	logged, err := listRegistryEvents(ctx, d.client, d.Tables.EventTableName, strings.ToLower(organization))
+	if err != nil {
+		return err
+	}
+	for _, event := range logged {
+		err = d.deleteRegistryEvent(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DynamoDBBackend) deleteRegistryEvent(ctx context.Context, event RegistryEvent) error {
+	return deleteItem(ctx, d.client, d.Tables.EventTableName, map[string]types.AttributeValue{
+		"organization": &types.AttributeValueMemberS{Value: event.Organization},
+		"event":        &types.AttributeValueMemberS{Value: event.Event},
+	})
+}
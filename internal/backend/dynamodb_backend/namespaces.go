@@ -0,0 +1,87 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"strings"
+	"time"
+)
+
+var _ backend.NamespacesBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.NamespacesResponse, error) {
+	ns, err := d.namespaceLookup(ctx, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResponse(parameters.Namespace, ns), nil
+}
+
+func (d *DynamoDBBackend) NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.NamespacesRequest) (*models.NamespacesResponse, error) {
+	ns, err := d.namespaceLookup(ctx, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if ns.ID == "" {
+		ns.ID = uuid.New().String()
+	}
+
+	attributes := request.Data.Attributes
+	if attributes.Public != nil {
+		ns.Public = *attributes.Public
+	}
+	if attributes.PublicModules != nil {
+		ns.PublicModules = *attributes.PublicModules
+	}
+	if attributes.PublicProviders != nil {
+		ns.PublicProviders = *attributes.PublicProviders
+	}
+	ns.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	err = setNamespace(ctx, d.client, d.Tables.NamespaceTableName, *ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResponse(parameters.Namespace, ns), nil
+}
+
+func (d *DynamoDBBackend) namespaceLookup(ctx context.Context, parameters registrytypes.APIParameters) (*Namespace, error) {
+	key := fmt.Sprintf("%s/%s", strings.ToLower(parameters.Organization), strings.ToLower(parameters.Namespace))
+
+	ns, err := getNamespace(ctx, d.client, d.Tables.NamespaceTableName, key)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		ns = &Namespace{
+			Namespace:       key,
+			PublicModules:   []string{},
+			PublicProviders: []string{},
+		}
+	}
+
+	return ns, nil
+}
+
+func namespaceResponse(name string, ns *Namespace) *models.NamespacesResponse {
+	return &models.NamespacesResponse{
+		Data: models.NamespacesDataResponse{
+			ID:   ns.ID,
+			Type: "registry-namespaces",
+			Attributes: models.NamespacesAttributesResponse{
+				Name:            name,
+				Public:          ns.Public,
+				PublicModules:   ns.PublicModules,
+				PublicProviders: ns.PublicProviders,
+				UpdatedAt:       ns.UpdatedAt,
+			},
+		},
+	}
+}
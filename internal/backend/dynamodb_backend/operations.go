@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
 	"strings"
 )
 
@@ -157,6 +158,659 @@ func getProviderVersion(ctx context.Context, client *dynamodb.Client, tableName
 	return nil, nil
 }
 
+func setNamespace(ctx context.Context, client *dynamodb.Client, tableName string, namespace Namespace) error {
+	item := map[string]types.AttributeValue{
+		"namespace":        &types.AttributeValueMemberS{Value: namespace.Namespace},
+		"id":               &types.AttributeValueMemberS{Value: namespace.ID},
+		"public":           &types.AttributeValueMemberBOOL{Value: namespace.Public},
+		"public_modules":   stringList(namespace.PublicModules),
+		"public_providers": stringList(namespace.PublicProviders),
+		"updated_at":       &types.AttributeValueMemberS{Value: namespace.UpdatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+func getNamespace(ctx context.Context, client *dynamodb.Client, tableName string, key string) (*Namespace, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("namespace = :n"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":n": &types.AttributeValueMemberS{Value: key},
+		},
+	}
+
+	resp, err := client.Query(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items, %v", err)
+	}
+
+	if resp.Count == 1 {
+		ns := &Namespace{
+			Namespace:       key,
+			ID:              resp.Items[0]["id"].(*types.AttributeValueMemberS).Value,
+			Public:          resp.Items[0]["public"].(*types.AttributeValueMemberBOOL).Value,
+			PublicModules:   fromStringList(resp.Items[0]["public_modules"]),
+			PublicProviders: fromStringList(resp.Items[0]["public_providers"]),
+			UpdatedAt:       resp.Items[0]["updated_at"].(*types.AttributeValueMemberS).Value,
+		}
+		return ns, nil
+	}
+
+	return nil, nil
+}
+
+func setShare(ctx context.Context, client *dynamodb.Client, tableName string, share Share) error {
+	item := map[string]types.AttributeValue{
+		"organization":  &types.AttributeValueMemberS{Value: share.Organization},
+		"id":            &types.AttributeValueMemberS{Value: share.ID},
+		"grantee":       &types.AttributeValueMemberS{Value: share.Grantee},
+		"resource_type": &types.AttributeValueMemberS{Value: share.ResourceType},
+		"namespace":     &types.AttributeValueMemberS{Value: share.Namespace},
+		"name":          &types.AttributeValueMemberS{Value: share.Name},
+		"provider":      &types.AttributeValueMemberS{Value: share.Provider},
+		"created_at":    &types.AttributeValueMemberS{Value: share.CreatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(organization) and attribute_not_exists(id)"),
+	})
+
+	return err
+}
+
+func listShares(ctx context.Context, client *dynamodb.Client, tableName string, organization string) ([]Share, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("organization = :o"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":o": &types.AttributeValueMemberS{Value: organization},
+		},
+	}
+
+	var shares []Share
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			shares = append(shares, shareItem(item))
+		}
+	}
+
+	return shares, nil
+}
+
+func shareItem(item map[string]types.AttributeValue) Share {
+	return Share{
+		Organization: item["organization"].(*types.AttributeValueMemberS).Value,
+		ID:           item["id"].(*types.AttributeValueMemberS).Value,
+		Grantee:      item["grantee"].(*types.AttributeValueMemberS).Value,
+		ResourceType: item["resource_type"].(*types.AttributeValueMemberS).Value,
+		Namespace:    item["namespace"].(*types.AttributeValueMemberS).Value,
+		Name:         item["name"].(*types.AttributeValueMemberS).Value,
+		Provider:     item["provider"].(*types.AttributeValueMemberS).Value,
+		CreatedAt:    item["created_at"].(*types.AttributeValueMemberS).Value,
+	}
+}
+
+func deleteShare(ctx context.Context, client *dynamodb.Client, tableName string, organization string, id string) (bool, error) {
+	resp, err := client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"organization": &types.AttributeValueMemberS{Value: organization},
+			"id":           &types.AttributeValueMemberS{Value: id},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(resp.Attributes) > 0, nil
+}
+
+func setOrganization(ctx context.Context, client *dynamodb.Client, tableName string, organization Organization) error {
+	item := map[string]types.AttributeValue{
+		"key":              &types.AttributeValueMemberS{Value: organization.Key},
+		"id":               &types.AttributeValueMemberS{Value: organization.ID},
+		"name":             &types.AttributeValueMemberS{Value: organization.Name},
+		"email":            &types.AttributeValueMemberS{Value: organization.Email},
+		"default_registry": &types.AttributeValueMemberS{Value: organization.DefaultRegistry},
+		"created_at":       &types.AttributeValueMemberS{Value: organization.CreatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+func getOrganization(ctx context.Context, client *dynamodb.Client, tableName string, key string) (*Organization, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#k = :k"),
+		ExpressionAttributeNames: map[string]string{
+			"#k": "key",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":k": &types.AttributeValueMemberS{Value: key},
+		},
+	}
+
+	resp, err := client.Query(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query items, %v", err)
+	}
+
+	if resp.Count == 1 {
+		return organizationItem(resp.Items[0]), nil
+	}
+
+	return nil, nil
+}
+
+func listOrganizations(ctx context.Context, client *dynamodb.Client, tableName string) ([]Organization, error) {
+	items, err := scanItems(ctx, client, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var organizations []Organization
+	for _, item := range items {
+		organizations = append(organizations, *organizationItem(item))
+	}
+
+	return organizations, nil
+}
+
+func organizationItem(item map[string]types.AttributeValue) *Organization {
+	organization := &Organization{
+		Key:             item["key"].(*types.AttributeValueMemberS).Value,
+		ID:              item["id"].(*types.AttributeValueMemberS).Value,
+		Name:            item["name"].(*types.AttributeValueMemberS).Value,
+		Email:           item["email"].(*types.AttributeValueMemberS).Value,
+		CreatedAt:       item["created_at"].(*types.AttributeValueMemberS).Value,
+		DefaultRegistry: "private",
+	}
+	if v, ok := item["default_registry"].(*types.AttributeValueMemberS); ok {
+		organization.DefaultRegistry = v.Value
+	}
+
+	return organization
+}
+
+// getAuditHead returns the sequence and hash of the last chained event of an
+// organization, zero values when there is none.
+func getAuditHead(ctx context.Context, client *dynamodb.Client, tableName string, organization string) (int64, string, error) {
+	resp, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"organization": &types.AttributeValueMemberS{Value: organization},
+			"event":        &types.AttributeValueMemberS{Value: auditHeadEvent},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	if resp.Item == nil {
+		return 0, "", nil
+	}
+
+	sequence, _ := strconv.ParseInt(resp.Item["sequence"].(*types.AttributeValueMemberN).Value, 10, 64)
+
+	return sequence, resp.Item["hash"].(*types.AttributeValueMemberS).Value, nil
+}
+
+// appendAuditEvent writes the event and moves the head in one transaction.
+// The transaction is cancelled when another writer moved the head since
+// headSequence was read.
+func appendAuditEvent(ctx context.Context, client *dynamodb.Client, tableName string, headSequence int64, event AuditEvent) error {
+	head := &types.Put{
+		TableName: aws.String(tableName),
+		Item: map[string]types.AttributeValue{
+			"organization": &types.AttributeValueMemberS{Value: event.Organization},
+			"event":        &types.AttributeValueMemberS{Value: auditHeadEvent},
+			"sequence":     &types.AttributeValueMemberN{Value: strconv.FormatInt(event.Sequence, 10)},
+			"hash":         &types.AttributeValueMemberS{Value: event.Hash},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(event)"),
+	}
+	if headSequence > 0 {
+		head.ConditionExpression = aws.String("#s = :s")
+		head.ExpressionAttributeNames = map[string]string{
+			"#s": "sequence",
+		}
+		head.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":s": &types.AttributeValueMemberN{Value: strconv.FormatInt(headSequence, 10)},
+		}
+	}
+
+	_, err := client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(tableName),
+					Item:                auditEventItem(event),
+					ConditionExpression: aws.String("attribute_not_exists(organization) and attribute_not_exists(event)"),
+				},
+			},
+			{
+				Put: head,
+			},
+		},
+	})
+
+	return err
+}
+
+func auditEventItem(event AuditEvent) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"organization": &types.AttributeValueMemberS{Value: event.Organization},
+		"event":        &types.AttributeValueMemberS{Value: event.Event},
+		"id":           &types.AttributeValueMemberS{Value: event.ID},
+		"actor":        &types.AttributeValueMemberS{Value: event.Actor},
+		"action":       &types.AttributeValueMemberS{Value: event.Action},
+		"target":       &types.AttributeValueMemberS{Value: event.Target},
+		"source_ip":    &types.AttributeValueMemberS{Value: event.SourceIP},
+		"outcome":      &types.AttributeValueMemberS{Value: event.Outcome},
+		"status_code":  &types.AttributeValueMemberN{Value: strconv.Itoa(event.StatusCode)},
+		"created_at":   &types.AttributeValueMemberS{Value: event.CreatedAt},
+		"sequence":     &types.AttributeValueMemberN{Value: strconv.FormatInt(event.Sequence, 10)},
+		"prev_hash":    &types.AttributeValueMemberS{Value: event.PrevHash},
+		"hash":         &types.AttributeValueMemberS{Value: event.Hash},
+	}
+}
+
+// listAuditEvents returns the events of an organization whose sort key falls
+// between from and to, in order.
+func listAuditEvents(ctx context.Context, client *dynamodb.Client, tableName string, organization string, from string, to string) ([]AuditEvent, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("organization = :o and #e between :f and :t"),
+		ExpressionAttributeNames: map[string]string{
+			"#e": "event",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":o": &types.AttributeValueMemberS{Value: organization},
+			":f": &types.AttributeValueMemberS{Value: from},
+			":t": &types.AttributeValueMemberS{Value: to},
+		},
+	}
+
+	var events []AuditEvent
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			statusCode, _ := strconv.Atoi(item["status_code"].(*types.AttributeValueMemberN).Value)
+			event := AuditEvent{
+				Organization: item["organization"].(*types.AttributeValueMemberS).Value,
+				Event:        item["event"].(*types.AttributeValueMemberS).Value,
+				ID:           item["id"].(*types.AttributeValueMemberS).Value,
+				Actor:        item["actor"].(*types.AttributeValueMemberS).Value,
+				Action:       item["action"].(*types.AttributeValueMemberS).Value,
+				Target:       item["target"].(*types.AttributeValueMemberS).Value,
+				SourceIP:     item["source_ip"].(*types.AttributeValueMemberS).Value,
+				Outcome:      item["outcome"].(*types.AttributeValueMemberS).Value,
+				StatusCode:   statusCode,
+				CreatedAt:    item["created_at"].(*types.AttributeValueMemberS).Value,
+			}
+
+			// Events recorded before the chain was introduced have no sequence
+			if sequence, ok := item["sequence"].(*types.AttributeValueMemberN); ok {
+				event.Sequence, _ = strconv.ParseInt(sequence.Value, 10, 64)
+				event.PrevHash = item["prev_hash"].(*types.AttributeValueMemberS).Value
+				event.Hash = item["hash"].(*types.AttributeValueMemberS).Value
+			}
+
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func setWebhook(ctx context.Context, client *dynamodb.Client, tableName string, webhook Webhook) error {
+	item := map[string]types.AttributeValue{
+		"organization": &types.AttributeValueMemberS{Value: webhook.Organization},
+		"id":           &types.AttributeValueMemberS{Value: webhook.ID},
+		"url":          &types.AttributeValueMemberS{Value: webhook.URL},
+		"events":       stringList(webhook.Events),
+		"secret":       &types.AttributeValueMemberS{Value: webhook.Secret},
+		"enabled":      &types.AttributeValueMemberBOOL{Value: webhook.Enabled},
+		"created_at":   &types.AttributeValueMemberS{Value: webhook.CreatedAt},
+		"updated_at":   &types.AttributeValueMemberS{Value: webhook.UpdatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+func getWebhook(ctx context.Context, client *dynamodb.Client, tableName string, organization string, id string) (*Webhook, error) {
+	resp, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"organization": &types.AttributeValueMemberS{Value: organization},
+			"id":           &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Item == nil {
+		return nil, nil
+	}
+
+	webhook := webhookItem(resp.Item)
+	return &webhook, nil
+}
+
+func listWebhooks(ctx context.Context, client *dynamodb.Client, tableName string, organization string) ([]Webhook, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("organization = :o"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":o": &types.AttributeValueMemberS{Value: organization},
+		},
+	}
+
+	var webhooks []Webhook
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			webhooks = append(webhooks, webhookItem(item))
+		}
+	}
+
+	return webhooks, nil
+}
+
+func webhookItem(item map[string]types.AttributeValue) Webhook {
+	return Webhook{
+		Organization: item["organization"].(*types.AttributeValueMemberS).Value,
+		ID:           item["id"].(*types.AttributeValueMemberS).Value,
+		URL:          item["url"].(*types.AttributeValueMemberS).Value,
+		Events:       fromStringList(item["events"]),
+		Secret:       item["secret"].(*types.AttributeValueMemberS).Value,
+		Enabled:      item["enabled"].(*types.AttributeValueMemberBOOL).Value,
+		CreatedAt:    item["created_at"].(*types.AttributeValueMemberS).Value,
+		UpdatedAt:    item["updated_at"].(*types.AttributeValueMemberS).Value,
+	}
+}
+
+func setWebhookDelivery(ctx context.Context, client *dynamodb.Client, tableName string, delivery WebhookDelivery) error {
+	item := map[string]types.AttributeValue{
+		"webhook_id":  &types.AttributeValueMemberS{Value: delivery.WebhookID},
+		"delivery":    &types.AttributeValueMemberS{Value: delivery.Delivery},
+		"id":          &types.AttributeValueMemberS{Value: delivery.ID},
+		"event_id":    &types.AttributeValueMemberS{Value: delivery.EventID},
+		"event_type":  &types.AttributeValueMemberS{Value: delivery.EventType},
+		"attempt":     &types.AttributeValueMemberN{Value: strconv.Itoa(delivery.Attempt)},
+		"status_code": &types.AttributeValueMemberN{Value: strconv.Itoa(delivery.StatusCode)},
+		"error":       &types.AttributeValueMemberS{Value: delivery.Error},
+		"duration_ms": &types.AttributeValueMemberN{Value: strconv.FormatInt(delivery.DurationMS, 10)},
+		"success":     &types.AttributeValueMemberBOOL{Value: delivery.Success},
+		"created_at":  &types.AttributeValueMemberS{Value: delivery.CreatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+// listWebhookDeliveries returns the delivery attempts of a webhook, oldest
+// first.
+func listWebhookDeliveries(ctx context.Context, client *dynamodb.Client, tableName string, webhookID string) ([]WebhookDelivery, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("webhook_id = :w"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":w": &types.AttributeValueMemberS{Value: webhookID},
+		},
+	}
+
+	var deliveries []WebhookDelivery
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			attempt, _ := strconv.Atoi(item["attempt"].(*types.AttributeValueMemberN).Value)
+			statusCode, _ := strconv.Atoi(item["status_code"].(*types.AttributeValueMemberN).Value)
+			duration, _ := strconv.ParseInt(item["duration_ms"].(*types.AttributeValueMemberN).Value, 10, 64)
+			deliveries = append(deliveries, WebhookDelivery{
+				WebhookID:  item["webhook_id"].(*types.AttributeValueMemberS).Value,
+				Delivery:   item["delivery"].(*types.AttributeValueMemberS).Value,
+				ID:         item["id"].(*types.AttributeValueMemberS).Value,
+				EventID:    item["event_id"].(*types.AttributeValueMemberS).Value,
+				EventType:  item["event_type"].(*types.AttributeValueMemberS).Value,
+				Attempt:    attempt,
+				StatusCode: statusCode,
+				Error:      item["error"].(*types.AttributeValueMemberS).Value,
+				DurationMS: duration,
+				Success:    item["success"].(*types.AttributeValueMemberBOOL).Value,
+				CreatedAt:  item["created_at"].(*types.AttributeValueMemberS).Value,
+			})
+		}
+	}
+
+	return deliveries, nil
+}
+
+func setRegistryEvent(ctx context.Context, client *dynamodb.Client, tableName string, event RegistryEvent) error {
+	data := map[string]types.AttributeValue{}
+	for k, v := range event.Data {
+		data[k] = &types.AttributeValueMemberS{Value: v}
+	}
+
+	item := map[string]types.AttributeValue{
+		"organization": &types.AttributeValueMemberS{Value: event.Organization},
+		"event":        &types.AttributeValueMemberS{Value: event.Event},
+		"id":           &types.AttributeValueMemberS{Value: event.ID},
+		"type":         &types.AttributeValueMemberS{Value: event.Type},
+		"subject":      &types.AttributeValueMemberS{Value: event.Subject},
+		"data":         &types.AttributeValueMemberM{Value: data},
+		"created_at":   &types.AttributeValueMemberS{Value: event.CreatedAt},
+	}
+
+	_, err := client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+// listRegistryEvents returns the event log of an organization, oldest first.
+func listRegistryEvents(ctx context.Context, client *dynamodb.Client, tableName string, organization string) ([]RegistryEvent, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("organization = :o"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":o": &types.AttributeValueMemberS{Value: organization},
+		},
+	}
+
+	var events []RegistryEvent
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			data := map[string]string{}
+			if m, ok := item["data"].(*types.AttributeValueMemberM); ok {
+				for k, v := range m.Value {
+					if s, ok := v.(*types.AttributeValueMemberS); ok {
+						data[k] = s.Value
+					}
+				}
+			}
+
+			events = append(events, RegistryEvent{
+				Organization: item["organization"].(*types.AttributeValueMemberS).Value,
+				Event:        item["event"].(*types.AttributeValueMemberS).Value,
+				ID:           item["id"].(*types.AttributeValueMemberS).Value,
+				Type:         item["type"].(*types.AttributeValueMemberS).Value,
+				Subject:      item["subject"].(*types.AttributeValueMemberS).Value,
+				Data:         data,
+				CreatedAt:    item["created_at"].(*types.AttributeValueMemberS).Value,
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// incrementDownloadCounter adds one to a counter, creating it on first use.
+func incrementDownloadCounter(ctx context.Context, client *dynamodb.Client, tableName string, counter DownloadCounter) error {
+	params := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"subject": &types.AttributeValueMemberS{Value: counter.Subject},
+			"counter": &types.AttributeValueMemberS{Value: counter.Counter},
+		},
+		UpdateExpression: aws.String("SET #v = :v, #d = :d, #k = :k ADD #c :one"),
+		ExpressionAttributeNames: map[string]string{
+			"#v": "version",
+			"#d": "dimension",
+			"#k": "key",
+			"#c": "count",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v":   &types.AttributeValueMemberS{Value: counter.Version},
+			":d":   &types.AttributeValueMemberS{Value: counter.Dimension},
+			":k":   &types.AttributeValueMemberS{Value: counter.Key},
+			":one": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	_, err := client.UpdateItem(ctx, params)
+
+	return err
+}
+
+func listDownloadCounters(ctx context.Context, client *dynamodb.Client, tableName string, subject string) ([]DownloadCounter, error) {
+	params := &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("subject = :s"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":s": &types.AttributeValueMemberS{Value: subject},
+		},
+	}
+
+	var counters []DownloadCounter
+	paginator := dynamodb.NewQueryPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query items, %v", err)
+		}
+
+		for _, item := range resp.Items {
+			counters = append(counters, downloadCounterItem(item))
+		}
+	}
+
+	return counters, nil
+}
+
+func downloadCounterItem(item map[string]types.AttributeValue) DownloadCounter {
+	count, _ := strconv.ParseInt(item["count"].(*types.AttributeValueMemberN).Value, 10, 64)
+	return DownloadCounter{
+		Subject:   item["subject"].(*types.AttributeValueMemberS).Value,
+		Counter:   item["counter"].(*types.AttributeValueMemberS).Value,
+		Version:   item["version"].(*types.AttributeValueMemberS).Value,
+		Dimension: item["dimension"].(*types.AttributeValueMemberS).Value,
+		Key:       item["key"].(*types.AttributeValueMemberS).Value,
+		Count:     count,
+	}
+}
+
+func scanItems(ctx context.Context, client *dynamodb.Client, tableName string) ([]map[string]types.AttributeValue, error) {
+	params := &dynamodb.ScanInput{
+		TableName: aws.String(tableName),
+	}
+
+	var items []map[string]types.AttributeValue
+	paginator := dynamodb.NewScanPaginator(client, params)
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan items, %v", err)
+		}
+		items = append(items, resp.Items...)
+	}
+
+	return items, nil
+}
+
+func deleteItem(ctx context.Context, client *dynamodb.Client, tableName string, key map[string]types.AttributeValue) error {
+	_, err := client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+	})
+
+	return err
+}
+
+func stringList(values []string) *types.AttributeValueMemberL {
+	list := &types.AttributeValueMemberL{Value: []types.AttributeValue{}}
+	for _, v := range values {
+		list.Value = append(list.Value, &types.AttributeValueMemberS{Value: v})
+	}
+
+	return list
+}
+
+func fromStringList(attr types.AttributeValue) []string {
+	values := []string{}
+	if list, ok := attr.(*types.AttributeValueMemberL); ok {
+		for _, v := range list.Value {
+			if s, ok := v.(*types.AttributeValueMemberS); ok {
+				values = append(values, s.Value)
+			}
+		}
+	}
+
+	return values
+}
+
 func duplicatePlatform(platforms []ProviderPlatform, os string, arch string) bool {
 	for _, platform := range platforms {
 		if strings.EqualFold(platform.OS, os) && strings.EqualFold(platform.Arch, arch) {
@@ -0,0 +1,522 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.OrganizationsBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) OrganizationsList(ctx context.Context) (*models.OrganizationsListResponse, error) {
+	organizations, err := listOrganizations(ctx, d.client, d.Tables.OrganizationTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.OrganizationsListResponse{
+		Data: []models.OrganizationsDataResponse{},
+	}
+
+	for _, organization := range organizations {
+		resp.Data = append(resp.Data, organizationResponse(organization).Data)
+	}
+
+	return resp, nil
+}
+
+func (d *DynamoDBBackend) OrganizationsCreate(ctx context.Context, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	organization := &Organization{
+		ID:              uuid.New().String(),
+		DefaultRegistry: "private",
+		CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+	}
+	applyOrganizationAttributes(organization, request.Data.Attributes)
+	organization.Key = strings.ToLower(organization.Name)
+
+	existing, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, organization.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("organization already exists")
+	}
+
+	err = setOrganization(ctx, d.client, d.Tables.OrganizationTableName, *organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (d *DynamoDBBackend) OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.OrganizationsResponse, error) {
+	organization, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, nil
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (d *DynamoDBBackend) OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	organization, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, fmt.Errorf("organization not found")
+	}
+
+	name := organization.Name
+	applyOrganizationAttributes(organization, request.Data.Attributes)
+
+	if organization.Name != name {
+		err = d.organizationRename(ctx, name, organization.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if organization.Key != strings.ToLower(organization.Name) {
+		err = deleteItem(ctx, d.client, d.Tables.OrganizationTableName, map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: organization.Key},
+		})
+		if err != nil {
+			return nil, err
+		}
+		organization.Key = strings.ToLower(organization.Name)
+	}
+
+	err = setOrganization(ctx, d.client, d.Tables.OrganizationTableName, *organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (d *DynamoDBBackend) OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	organization, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if organization == nil {
+		return http.StatusNotFound, fmt.Errorf("organization not found")
+	}
+
+	providers, err := d.organizationProviders(ctx, organization.Name)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	versions, err := scanItems(ctx, d.client, d.Tables.ProviderVersionTableName)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, item := range versions {
+		provider := item["provider"].(*types.AttributeValueMemberS).Value
+		if !providers[provider] {
+			continue
+		}
+		err = deleteItem(ctx, d.client, d.Tables.ProviderVersionTableName, map[string]types.AttributeValue{
+			"provider": item["provider"],
+			"version":  item["version"],
+		})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	for provider := range providers {
+		err = deleteItem(ctx, d.client, d.Tables.ProviderTableName, map[string]types.AttributeValue{
+			"provider": &types.AttributeValueMemberS{Value: provider},
+		})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	namespaces, err := d.organizationNamespaces(ctx, organization.Name)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, item := range namespaces {
+		err = deleteItem(ctx, d.client, d.Tables.NamespaceTableName, map[string]types.AttributeValue{
+			"namespace": item["namespace"],
+		})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	gpgKeys, err := d.organizationGPGKeys(ctx, organization.Name)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, item := range gpgKeys {
+		err = deleteItem(ctx, d.client, d.Tables.GPGTableName, map[string]types.AttributeValue{
+			"namespace": item["namespace"],
+			"key_id":    item["key_id"],
+		})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	shares, err := d.organizationShares(ctx, organization.Name)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, share := range shares {
+		_, err = deleteShare(ctx, d.client, d.Tables.ShareTableName, share.Organization, share.ID)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	webhooks, err := listWebhooks(ctx, d.client, d.Tables.WebhookTableName, organization.Key)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, webhook := range webhooks {
+		err = d.deleteWebhook(ctx, webhook)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+
+	err = d.deleteEventLog(ctx, organization.Key)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	err = d.deleteDownloadCounters(ctx, organization.Key)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	err = deleteItem(ctx, d.client, d.Tables.OrganizationTableName, map[string]types.AttributeValue{
+		"key": &types.AttributeValueMemberS{Value: organization.Key},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusNoContent, nil
+}
+
+// organizationRename moves namespaces, shares, GPG keys and webhooks to the
+// new name and drops the event log and download counters, which only outlive
+// deleted providers. Providers are refused since their storage paths and
+// registry addresses contain the organization name.
+func (d *DynamoDBBackend) organizationRename(ctx context.Context, name string, newName string) error {
+	if !strings.EqualFold(name, newName) {
+		existing, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, strings.ToLower(newName))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("organization already exists")
+		}
+	}
+
+	providers, err := d.organizationProviders(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(providers) > 0 {
+		return fmt.Errorf("organization with providers or modules cannot be renamed")
+	}
+
+	namespaces, err := d.organizationNamespaces(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, item := range namespaces {
+		ns, err := getNamespace(ctx, d.client, d.Tables.NamespaceTableName, item["namespace"].(*types.AttributeValueMemberS).Value)
+		if err != nil || ns == nil {
+			return err
+		}
+		err = deleteItem(ctx, d.client, d.Tables.NamespaceTableName, map[string]types.AttributeValue{
+			"namespace": item["namespace"],
+		})
+		if err != nil {
+			return err
+		}
+
+		_, namespace, _ := strings.Cut(ns.Namespace, "/")
+		if namespace == strings.ToLower(name) {
+			namespace = strings.ToLower(newName)
+		}
+		ns.Namespace = fmt.Sprintf("%s/%s", strings.ToLower(newName), namespace)
+		err = setNamespace(ctx, d.client, d.Tables.NamespaceTableName, *ns)
+		if err != nil {
+			return err
+		}
+	}
+
+	gpgKeys, err := d.organizationGPGKeys(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, item := range gpgKeys {
+		err = deleteItem(ctx, d.client, d.Tables.GPGTableName, map[string]types.AttributeValue{
+			"namespace": item["namespace"],
+			"key_id":    item["key_id"],
+		})
+		if err != nil {
+			return err
+		}
+
+		err = setGPG(ctx, d.client, d.Tables.GPGTableName, GPGKey{
+			Namespace:  newName,
+			KeyID:      item["key_id"].(*types.AttributeValueMemberS).Value,
+			ID:         item["id"].(*types.AttributeValueMemberS).Value,
+			AsciiArmor: item["ascii_armor"].(*types.AttributeValueMemberS).Value,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	shares, err := d.organizationShares(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		_, err = deleteShare(ctx, d.client, d.Tables.ShareTableName, share.Organization, share.ID)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(share.Grantee, name) {
+			share.Grantee = newName
+		}
+		if strings.EqualFold(share.Organization, name) {
+			share.Organization = strings.ToLower(newName)
+			if strings.EqualFold(share.Namespace, name) {
+				share.Namespace = newName
+			}
+		}
+		err = setShare(ctx, d.client, d.Tables.ShareTableName, share)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Deliveries are keyed by webhook id and stay in place
+	webhooks, err := listWebhooks(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(name))
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		err = deleteItem(ctx, d.client, d.Tables.WebhookTableName, map[string]types.AttributeValue{
+			"organization": &types.AttributeValueMemberS{Value: webhook.Organization},
+			"id":           &types.AttributeValueMemberS{Value: webhook.ID},
+		})
+		if err != nil {
+			return err
+		}
+
+		webhook.Organization = strings.ToLower(newName)
+		err = setWebhook(ctx, d.client, d.Tables.WebhookTableName, webhook)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = d.deleteEventLog(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return d.deleteDownloadCounters(ctx, name)
+}
+
+// organizationProviders returns the provider keys owned by the organization.
+// Keys keep the case of the request that created them.
+func (d *DynamoDBBackend) organizationProviders(ctx context.Context, organization string) (map[string]bool, error) {
+	items, err := scanItems(ctx, d.client, d.Tables.ProviderTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := map[string]bool{}
+	for _, item := range items {
+		provider := item["provider"].(*types.AttributeValueMemberS).Value
+		owner, _, _ := strings.Cut(provider, ":")
+		if strings.EqualFold(owner, organization) {
+			providers[provider] = true
+		}
+	}
+
+	return providers, nil
+}
+
+func (d *DynamoDBBackend) organizationNamespaces(ctx context.Context, organization string) ([]map[string]types.AttributeValue, error) {
+	items, err := scanItems(ctx, d.client, d.Tables.NamespaceTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []map[string]types.AttributeValue
+	for _, item := range items {
+		if strings.HasPrefix(item["namespace"].(*types.AttributeValueMemberS).Value, strings.ToLower(organization)+"/") {
+			namespaces = append(namespaces, item)
+		}
+	}
+
+	return namespaces, nil
+}
+
+func (d *DynamoDBBackend) organizationGPGKeys(ctx context.Context, organization string) ([]map[string]types.AttributeValue, error) {
+	items, err := scanItems(ctx, d.client, d.Tables.GPGTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var gpgKeys []map[string]types.AttributeValue
+	for _, item := range items {
+		if strings.EqualFold(item["namespace"].(*types.AttributeValueMemberS).Value, organization) {
+			gpgKeys = append(gpgKeys, item)
+		}
+	}
+
+	return gpgKeys, nil
+}
+
+// organizationShares returns shares granted by or to the organization.
+func (d *DynamoDBBackend) organizationShares(ctx context.Context, organization string) ([]Share, error) {
+	items, err := scanItems(ctx, d.client, d.Tables.ShareTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []Share
+	for _, item := range items {
+		share := shareItem(item)
+		if strings.EqualFold(share.Organization, organization) || strings.EqualFold(share.Grantee, organization) {
+			shares = append(shares, share)
+		}
+	}
+
+	return shares, nil
+}
+
+// organizationsBackfill creates the records of organizations that are only
+// referenced by providers, namespaces or shares written before organizations
+// were stored, the equivalent of the Postgres migration.
+func (d *DynamoDBBackend) organizationsBackfill(ctx context.Context) error {
+	providers, err := scanItems(ctx, d.client, d.Tables.ProviderTableName)
+	if err != nil {
+		return err
+	}
+	namespaces, err := scanItems(ctx, d.client, d.Tables.NamespaceTableName)
+	if err != nil {
+		return err
+	}
+	shares, err := scanItems(ctx, d.client, d.Tables.ShareTableName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range referencedOrganizations(providers, namespaces, shares) {
+		existing, err := getOrganization(ctx, d.client, d.Tables.OrganizationTableName, strings.ToLower(name))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			continue
+		}
+
+		err = setOrganization(ctx, d.client, d.Tables.OrganizationTableName, Organization{
+			Key:             strings.ToLower(name),
+			ID:              uuid.New().String(),
+			Name:            name,
+			DefaultRegistry: "private",
+			CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+
+		slog.InfoContext(ctx, "Created organization for existing data", "organization", name)
+	}
+
+	return nil
+}
+
+// referencedOrganizations returns the organizations named in provider keys
+// (organization:registry:namespace/name), namespace keys
+// (organization/namespace) and shares, once per name ignoring case.
+func referencedOrganizations(providers, namespaces, shares []map[string]types.AttributeValue) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name == "" || seen[strings.ToLower(name)] {
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+
+	for _, item := range providers {
+		if v, ok := item["provider"].(*types.AttributeValueMemberS); ok {
+			name, _, _ := strings.Cut(v.Value, ":")
+			add(name)
+		}
+	}
+	for _, item := range namespaces {
+		if v, ok := item["namespace"].(*types.AttributeValueMemberS); ok {
+			name, _, _ := strings.Cut(v.Value, "/")
+			add(name)
+		}
+	}
+	for _, item := range shares {
+		if v, ok := item["organization"].(*types.AttributeValueMemberS); ok {
+			add(v.Value)
+		}
+	}
+
+	return names
+}
+
+func applyOrganizationAttributes(organization *Organization, attributes models.OrganizationsAttributesRequest) {
+	if attributes.Name != nil {
+		organization.Name = *attributes.Name
+	}
+	if attributes.Email != nil {
+		organization.Email = *attributes.Email
+	}
+	if attributes.DefaultRegistry != nil {
+		organization.DefaultRegistry = *attributes.DefaultRegistry
+	}
+}
+
+func organizationResponse(organization Organization) *models.OrganizationsResponse {
+	return &models.OrganizationsResponse{
+		Data: models.OrganizationsDataResponse{
+			ID:   organization.Name,
+			Type: "organizations",
+			Attributes: models.OrganizationsAttributesResponse{
+				Name:            organization.Name,
+				Email:           organization.Email,
+				DefaultRegistry: organization.DefaultRegistry,
+				ExternalID:      organization.ID,
+				CreatedAt:       organization.CreatedAt,
+			},
+		},
+	}
+}
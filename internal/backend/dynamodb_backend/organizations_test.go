@@ -0,0 +1,31 @@
+package dynamodb_backend
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"slices"
+	"testing"
+)
+
+func TestReferencedOrganizations(t *testing.T) {
+	item := func(name string, value string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{name: &types.AttributeValueMemberS{Value: value}}
+	}
+
+	providers := []map[string]types.AttributeValue{
+		item("provider", "Acme:private:acme/aws"),
+		item("provider", "acme:private:acme/google"),
+	}
+	namespaces := []map[string]types.AttributeValue{
+		item("namespace", "acme/acme"),
+		item("namespace", "globex/globex"),
+	}
+	shares := []map[string]types.AttributeValue{
+		item("organization", "initech"),
+		item("organization", ""),
+	}
+
+	got := referencedOrganizations(providers, namespaces, shares)
+	if want := []string{"Acme", "globex", "initech"}; !slices.Equal(got, want) {
+		t.Errorf("referencedOrganizations() = %v, want %v", got, want)
+	}
+}
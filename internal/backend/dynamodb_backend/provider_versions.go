@@ -27,7 +27,7 @@ func (d *DynamoDBBackend) ProviderVersionsCreate(ctx context.Context, parameters
 		return nil, err
 	}
 	if gpg == nil {
-		return nil, fmt.Errorf("no GPG key found for %s", request.Data.Attributes.KeyID)
+		return nil, backend.ErrSigningKeyNotFound
 	}
 
 	newUUID := uuid.New()
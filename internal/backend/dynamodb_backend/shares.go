@@ -0,0 +1,130 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.SharesBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*models.SharesListResponse, error) {
+	shares, err := listShares(ctx, d.client, d.Tables.ShareTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.SharesListResponse{
+		Data: []models.SharesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.PaginationMeta{
+				PageSize:    len(shares),
+				CurrentPage: 1,
+				TotalPages:  1,
+				TotalCount:  len(shares),
+			},
+		},
+	}
+
+	for _, share := range shares {
+		resp.Data = append(resp.Data, shareData(parameters.Organization, share))
+	}
+
+	return resp, nil
+}
+
+func (d *DynamoDBBackend) SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.SharesRequest) (*models.SharesResponse, error) {
+	share := Share{
+		Organization: strings.ToLower(parameters.Organization),
+		ID:           uuid.New().String(),
+		Grantee:      request.Data.Attributes.GranteeOrganization,
+		ResourceType: request.Data.Attributes.ResourceType,
+		Namespace:    request.Data.Attributes.Namespace,
+		Name:         request.Data.Attributes.Name,
+		Provider:     request.Data.Attributes.Provider,
+		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+
+	existing, err := listShares(ctx, d.client, d.Tables.ShareTableName, share.Organization)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		if sameShare(e, share) {
+			return nil, fmt.Errorf("share already exists")
+		}
+	}
+
+	err = setShare(ctx, d.client, d.Tables.ShareTableName, share)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharesResponse{
+		Data: shareData(parameters.Organization, share),
+	}, nil
+}
+
+func (d *DynamoDBBackend) SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error) {
+	deleted, err := deleteShare(ctx, d.client, d.Tables.ShareTableName, strings.ToLower(parameters.Organization), shareID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, fmt.Errorf("share not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (d *DynamoDBBackend) SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error) {
+	shares, err := listShares(ctx, d.client, d.Tables.ShareTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return false, err
+	}
+
+	for _, share := range shares {
+		if share.ResourceType == parameters.ResourceType &&
+			strings.EqualFold(share.Namespace, parameters.Namespace) &&
+			strings.EqualFold(share.Name, parameters.Name) &&
+			strings.EqualFold(share.Provider, parameters.Provider) {
+			for _, grantee := range parameters.Grantees {
+				if strings.EqualFold(share.Grantee, grantee) {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func sameShare(a Share, b Share) bool {
+	return strings.EqualFold(a.Grantee, b.Grantee) &&
+		a.ResourceType == b.ResourceType &&
+		strings.EqualFold(a.Namespace, b.Namespace) &&
+		strings.EqualFold(a.Name, b.Name) &&
+		strings.EqualFold(a.Provider, b.Provider)
+}
+
+func shareData(organization string, share Share) models.SharesDataResponse {
+	return models.SharesDataResponse{
+		ID:   share.ID,
+		Type: "registry-shares",
+		Attributes: models.SharesAttributesResponse{
+			Organization:        organization,
+			GranteeOrganization: share.Grantee,
+			ResourceType:        share.ResourceType,
+			Namespace:           share.Namespace,
+			Name:                share.Name,
+			Provider:            share.Provider,
+			CreatedAt:           share.CreatedAt,
+		},
+	}
+}
@@ -28,3 +28,92 @@ type ProviderPlatform struct {
 	SHASum   string `json:"shasum"`
 	Filename string `json:"filename"`
 }
+
+type Namespace struct {
+	Namespace       string   `json:"namespace"`
+	ID              string   `json:"id"`
+	Public          bool     `json:"public"`
+	PublicModules   []string `json:"public_modules"`
+	PublicProviders []string `json:"public_providers"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type Share struct {
+	Organization string `json:"organization"`
+	ID           string `json:"id"`
+	Grantee      string `json:"grantee"`
+	ResourceType string `json:"resource_type"`
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	Provider     string `json:"provider"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type Organization struct {
+	Key             string `json:"key"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	DefaultRegistry string `json:"default_registry"`
+	CreatedAt       string `json:"created_at"`
+}
+
+type AuditEvent struct {
+	Organization string `json:"organization"`
+	Event        string `json:"event"`
+	ID           string `json:"id"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	Target       string `json:"target"`
+	SourceIP     string `json:"source_ip"`
+	Outcome      string `json:"outcome"`
+	StatusCode   int    `json:"status_code"`
+	CreatedAt    string `json:"created_at"`
+	Sequence     int64  `json:"sequence"`
+	PrevHash     string `json:"prev_hash"`
+	Hash         string `json:"hash"`
+}
+
+type Webhook struct {
+	Organization string   `json:"organization"`
+	ID           string   `json:"id"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`
+	Secret       string   `json:"secret"`
+	Enabled      bool     `json:"enabled"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	WebhookID  string `json:"webhook_id"`
+	Delivery   string `json:"delivery"`
+	ID         string `json:"id"`
+	EventID    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+	DurationMS int64  `json:"duration_ms"`
+	Success    bool   `json:"success"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type RegistryEvent struct {
+	Organization string            `json:"organization"`
+	Event        string            `json:"event"`
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Subject      string            `json:"subject"`
+	Data         map[string]string `json:"data"`
+	CreatedAt    string            `json:"created_at"`
+}
+
+type DownloadCounter struct {
+	Subject   string `json:"subject"`
+	Counter   string `json:"counter"`
+	Version   string `json:"version"`
+	Dimension string `json:"dimension"`
+	Key       string `json:"key"`
+	Count     int64  `json:"count"`
+}
@@ -0,0 +1,261 @@
+package dynamodb_backend
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.WebhooksBackend = &DynamoDBBackend{}
+
+func (d *DynamoDBBackend) WebhooksList(ctx context.Context, parameters registrytypes.APIParameters) (*models.WebhooksListResponse, error) {
+	webhooks, err := listWebhooks(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(parameters.Organization))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhooksListResponse{
+		Data: []models.WebhooksDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(1, len(webhooks), len(webhooks)),
+		},
+	}
+
+	for _, webhook := range webhooks {
+		resp.Data = append(resp.Data, webhookData(webhook))
+	}
+
+	return resp, nil
+}
+
+func (d *DynamoDBBackend) WebhooksCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	webhook := Webhook{
+		Organization: strings.ToLower(parameters.Organization),
+		ID:           uuid.New().String(),
+		Events:       []string{},
+		Enabled:      true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	applyWebhookAttributes(&webhook, request.Data.Attributes)
+
+	err := setWebhook(ctx, d.client, d.Tables.WebhookTableName, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(webhook),
+	}, nil
+}
+
+func (d *DynamoDBBackend) WebhooksGet(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (*models.WebhooksResponse, error) {
+	webhook, err := getWebhook(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(parameters.Organization), webhookID)
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (d *DynamoDBBackend) WebhooksUpdate(ctx context.Context, parameters registrytypes.APIParameters, webhookID string, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	webhook, err := getWebhook(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(parameters.Organization), webhookID)
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	applyWebhookAttributes(webhook, request.Data.Attributes)
+	webhook.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	err = setWebhook(ctx, d.client, d.Tables.WebhookTableName, *webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (d *DynamoDBBackend) WebhooksDelete(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (int, error) {
+	webhook, err := getWebhook(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(parameters.Organization), webhookID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if webhook == nil {
+		return http.StatusNotFound, fmt.Errorf("webhook not found")
+	}
+
+	err = d.deleteWebhook(ctx, *webhook)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (d *DynamoDBBackend) WebhooksSubscribed(ctx context.Context, organization string) ([]registrytypes.Webhook, error) {
+	webhooks, err := listWebhooks(ctx, d.client, d.Tables.WebhookTableName, strings.ToLower(organization))
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []registrytypes.Webhook
+	for _, webhook := range webhooks {
+		if !webhook.Enabled {
+			continue
+		}
+
+		subscribed = append(subscribed, registrytypes.Webhook{
+			ID:           webhook.ID,
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Secret:       webhook.Secret,
+			Enabled:      webhook.Enabled,
+		})
+	}
+
+	return subscribed, nil
+}
+
+func (d *DynamoDBBackend) WebhookDeliveriesCreate(ctx context.Context, delivery registrytypes.WebhookDelivery) error {
+	id := uuid.New().String()
+	timestamp := delivery.Timestamp.UTC()
+
+	value := WebhookDelivery{
+		WebhookID:  delivery.WebhookID,
+		Delivery:   timestamp.Format(auditSortFormat) + "#" + id,
+		ID:         id,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Error:      delivery.Error,
+		DurationMS: delivery.Duration.Milliseconds(),
+		Success:    delivery.Success,
+		CreatedAt:  timestamp.Format(time.RFC3339Nano),
+	}
+
+	err := setWebhookDelivery(ctx, d.client, d.Tables.WebhookDeliveryTableName, value)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := listWebhookDeliveries(ctx, d.client, d.Tables.WebhookDeliveryTableName, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(deliveries)-backend.WebhookDeliveryRetention; i++ {
+		err = deleteItem(ctx, d.client, d.Tables.WebhookDeliveryTableName, map[string]types.AttributeValue{
+			"webhook_id": &types.AttributeValueMemberS{Value: deliveries[i].WebhookID},
+			"delivery":   &types.AttributeValueMemberS{Value: deliveries[i].Delivery},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DynamoDBBackend) WebhookDeliveriesList(ctx context.Context, parameters registrytypes.WebhookDeliveryParameters) (*models.WebhookDeliveriesListResponse, error) {
+	deliveries, err := listWebhookDeliveries(ctx, d.client, d.Tables.WebhookDeliveryTableName, parameters.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhookDeliveriesListResponse{
+		Data: []models.WebhookDeliveriesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, len(deliveries)),
+		},
+	}
+
+	// Newest first
+	start := (parameters.PageNumber - 1) * parameters.PageSize
+	for i := start; i < len(deliveries) && i < start+parameters.PageSize; i++ {
+		resp.Data = append(resp.Data, webhookDeliveryData(deliveries[len(deliveries)-1-i]))
+	}
+
+	return resp, nil
+}
+
+// deleteWebhook removes the webhook along with its delivery log.
+func (d *DynamoDBBackend) deleteWebhook(ctx context.Context, webhook Webhook) error {
+	deliveries, err := listWebhookDeliveries(ctx, d.client, d.Tables.WebhookDeliveryTableName, webhook.ID)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		err = deleteItem(ctx, d.client, d.Tables.WebhookDeliveryTableName, map[string]types.AttributeValue{
+			"webhook_id": &types.AttributeValueMemberS{Value: delivery.WebhookID},
+			"delivery":   &types.AttributeValueMemberS{Value: delivery.Delivery},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return deleteItem(ctx, d.client, d.Tables.WebhookTableName, map[string]types.AttributeValue{
+		"organization": &types.AttributeValueMemberS{Value: webhook.Organization},
+		"id":           &types.AttributeValueMemberS{Value: webhook.ID},
+	})
+}
+
+func applyWebhookAttributes(webhook *Webhook, attributes models.WebhooksAttributesRequest) {
+	if attributes.URL != nil {
+		webhook.URL = *attributes.URL
+	}
+	if attributes.Events != nil {
+		webhook.Events = *attributes.Events
+	}
+	if attributes.Secret != nil {
+		webhook.Secret = *attributes.Secret
+	}
+	if attributes.Enabled != nil {
+		webhook.Enabled = *attributes.Enabled
+	}
+}
+
+func webhookData(webhook Webhook) models.WebhooksDataResponse {
+	return models.WebhooksDataResponse{
+		ID:   webhook.ID,
+		Type: "webhooks",
+		Attributes: models.WebhooksAttributesResponse{
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Enabled:      webhook.Enabled,
+			CreatedAt:    webhook.CreatedAt,
+			UpdatedAt:    webhook.UpdatedAt,
+		},
+	}
+}
+
+func webhookDeliveryData(delivery WebhookDelivery) models.WebhookDeliveriesDataResponse {
+	return models.WebhookDeliveriesDataResponse{
+		ID:   delivery.ID,
+		Type: "webhook-deliveries",
+		Attributes: models.WebhookDeliveriesAttributesResponse{
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			DurationMS: delivery.DurationMS,
+			Success:    delivery.Success,
+			CreatedAt:  delivery.CreatedAt,
+		},
+	}
+}
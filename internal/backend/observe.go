@@ -0,0 +1,446 @@
+package backend
+
+import (
+	"context"
+	apimodels "go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/models"
+	registrytypes "go-terraform-registry/internal/types"
+	"time"
+)
+
+// Observer is called before every backend operation with the name of the
+// Backend method. The returned context is passed on to the operation and the
+// returned function is called with its error once it completes.
+type Observer func(ctx context.Context, operation string) (context.Context, func(error))
+
+// Observe returns a backend calling observer around every operation of b.
+func Observe(b *Backend, observer Observer) *Backend {
+	observed := &Backend{
+		BackendLifecycle:        b.BackendLifecycle,
+		RegistryBackend:         &observedRegistryBackend{next: b.RegistryBackend, observe: observer},
+		ProvidersBackend:        &observedProvidersBackend{next: b.ProvidersBackend, observe: observer},
+		ProviderVersionsBackend: &observedProviderVersionsBackend{next: b.ProviderVersionsBackend, observe: observer},
+		ModulesBackend:          &observedModulesBackend{next: b.ModulesBackend, observe: observer},
+		ModuleVersionsBackend:   &observedModuleVersionsBackend{next: b.ModuleVersionsBackend, observe: observer},
+		GPGKeysBackend:          &observedGPGKeysBackend{next: b.GPGKeysBackend, observe: observer},
+		NamespacesBackend:       &observedNamespacesBackend{next: b.NamespacesBackend, observe: observer},
+		SharesBackend:           &observedSharesBackend{next: b.SharesBackend, observe: observer},
+		OrganizationsBackend:    &observedOrganizationsBackend{next: b.OrganizationsBackend, observe: observer},
+		AuditBackend:            &observedAuditBackend{next: b.AuditBackend, observe: observer},
+		WebhooksBackend:         &observedWebhooksBackend{next: b.WebhooksBackend, observe: observer},
+		EventsBackend:           &observedEventsBackend{next: b.EventsBackend, observe: observer},
+		DownloadsBackend:        &observedDownloadsBackend{next: b.DownloadsBackend, observe: observer},
+	}
+	if b.RateLimitsBackend != nil {
+		observed.RateLimitsBackend = &observedRateLimitsBackend{next: b.RateLimitsBackend, observe: observer}
+	}
+	if b.CacheInvalidationBackend != nil {
+		observed.CacheInvalidationBackend = &observedCacheInvalidationBackend{next: b.CacheInvalidationBackend, observe: observer}
+	}
+
+	return observed
+}
+
+type observedRegistryBackend struct {
+	next    RegistryBackend
+	observe Observer
+}
+
+func (o *observedRegistryBackend) GetProvider(ctx context.Context, parameters registrytypes.ProviderPackageParameters, userParameters registrytypes.UserParameters) (*models.TerraformProviderPlatformResponse, error) {
+	ctx, done := o.observe(ctx, "GetProvider")
+	result, err := o.next.GetProvider(ctx, parameters, userParameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedRegistryBackend) GetProviderVersions(ctx context.Context, parameters registrytypes.ProviderVersionParameters, userParameters registrytypes.UserParameters) (*models.TerraformAvailableProvider, error) {
+	ctx, done := o.observe(ctx, "GetProviderVersions")
+	result, err := o.next.GetProviderVersions(ctx, parameters, userParameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedRegistryBackend) GetModuleVersions(ctx context.Context, parameters registrytypes.ModuleVersionParameters) (*models.TerraformAvailableModule, error) {
+	ctx, done := o.observe(ctx, "GetModuleVersions")
+	result, err := o.next.GetModuleVersions(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedRegistryBackend) GetModuleDownload(ctx context.Context, parameters registrytypes.ModuleDownloadParameters) (*string, error) {
+	ctx, done := o.observe(ctx, "GetModuleDownload")
+	result, err := o.next.GetModuleDownload(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedProvidersBackend struct {
+	next    ProvidersBackend
+	observe Observer
+}
+
+func (o *observedProvidersBackend) ProvidersCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ProvidersRequest) (*apimodels.ProvidersResponse, error) {
+	ctx, done := o.observe(ctx, "ProvidersCreate")
+	result, err := o.next.ProvidersCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedProvidersBackend) ProvidersGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ProvidersResponse, error) {
+	ctx, done := o.observe(ctx, "ProvidersGet")
+	result, err := o.next.ProvidersGet(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedProviderVersionsBackend struct {
+	next    ProviderVersionsBackend
+	observe Observer
+}
+
+func (o *observedProviderVersionsBackend) ProviderVersionsList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ProviderVersionsListResponse, error) {
+	ctx, done := o.observe(ctx, "ProviderVersionsList")
+	result, err := o.next.ProviderVersionsList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedProviderVersionsBackend) ProviderVersionsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ProviderVersionsRequest) (*apimodels.ProviderVersionsResponse, error) {
+	ctx, done := o.observe(ctx, "ProviderVersionsCreate")
+	result, err := o.next.ProviderVersionsCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedProviderVersionsBackend) ProviderVersionsGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ProviderVersionsResponse, error) {
+	ctx, done := o.observe(ctx, "ProviderVersionsGet")
+	result, err := o.next.ProviderVersionsGet(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedProviderVersionsBackend) ProviderVersionsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	ctx, done := o.observe(ctx, "ProviderVersionsDelete")
+	result, err := o.next.ProviderVersionsDelete(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedProviderVersionsBackend) ProviderVersionPlatformsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ProviderVersionPlatformsRequest) (*apimodels.ProviderVersionPlatformsResponse, error) {
+	ctx, done := o.observe(ctx, "ProviderVersionPlatformsCreate")
+	result, err := o.next.ProviderVersionPlatformsCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+type observedModulesBackend struct {
+	next    ModulesBackend
+	observe Observer
+}
+
+func (o *observedModulesBackend) ModulesCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ModulesRequest) (*apimodels.ModulesResponse, error) {
+	ctx, done := o.observe(ctx, "ModulesCreate")
+	result, err := o.next.ModulesCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedModulesBackend) ModulesGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ModulesResponse, error) {
+	ctx, done := o.observe(ctx, "ModulesGet")
+	result, err := o.next.ModulesGet(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedModuleVersionsBackend struct {
+	next    ModuleVersionsBackend
+	observe Observer
+}
+
+func (o *observedModuleVersionsBackend) ModuleVersionsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ModuleVersionsRequest) (*apimodels.ModuleVersionsResponse, error) {
+	ctx, done := o.observe(ctx, "ModuleVersionsCreate")
+	result, err := o.next.ModuleVersionsCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedModuleVersionsBackend) ModuleVersionsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	ctx, done := o.observe(ctx, "ModuleVersionsDelete")
+	result, err := o.next.ModuleVersionsDelete(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedGPGKeysBackend struct {
+	next    GPGKeysBackend
+	observe Observer
+}
+
+func (o *observedGPGKeysBackend) GPGKeysList(ctx context.Context, namespaceFilter string, pageNumber *int, pageSize *int) (*apimodels.GPGKeysListResponse, error) {
+	ctx, done := o.observe(ctx, "GPGKeysList")
+	result, err := o.next.GPGKeysList(ctx, namespaceFilter, pageNumber, pageSize)
+	done(err)
+	return result, err
+}
+
+func (o *observedGPGKeysBackend) GPGKeysAdd(ctx context.Context, request apimodels.GPGKeysRequest) (*apimodels.GPGKeysResponse, error) {
+	ctx, done := o.observe(ctx, "GPGKeysAdd")
+	result, err := o.next.GPGKeysAdd(ctx, request)
+	done(err)
+	return result, err
+}
+
+type observedNamespacesBackend struct {
+	next    NamespacesBackend
+	observe Observer
+}
+
+func (o *observedNamespacesBackend) NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.NamespacesResponse, error) {
+	ctx, done := o.observe(ctx, "NamespacesGet")
+	result, err := o.next.NamespacesGet(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedNamespacesBackend) NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.NamespacesRequest) (*apimodels.NamespacesResponse, error) {
+	ctx, done := o.observe(ctx, "NamespacesUpdate")
+	result, err := o.next.NamespacesUpdate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+type observedSharesBackend struct {
+	next    SharesBackend
+	observe Observer
+}
+
+func (o *observedSharesBackend) SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.SharesListResponse, error) {
+	ctx, done := o.observe(ctx, "SharesList")
+	result, err := o.next.SharesList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedSharesBackend) SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.SharesRequest) (*apimodels.SharesResponse, error) {
+	ctx, done := o.observe(ctx, "SharesCreate")
+	result, err := o.next.SharesCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedSharesBackend) SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error) {
+	ctx, done := o.observe(ctx, "SharesDelete")
+	result, err := o.next.SharesDelete(ctx, parameters, shareID)
+	done(err)
+	return result, err
+}
+
+func (o *observedSharesBackend) SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error) {
+	ctx, done := o.observe(ctx, "SharesGranted")
+	result, err := o.next.SharesGranted(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedOrganizationsBackend struct {
+	next    OrganizationsBackend
+	observe Observer
+}
+
+func (o *observedOrganizationsBackend) OrganizationsList(ctx context.Context) (*apimodels.OrganizationsListResponse, error) {
+	ctx, done := o.observe(ctx, "OrganizationsList")
+	result, err := o.next.OrganizationsList(ctx)
+	done(err)
+	return result, err
+}
+
+func (o *observedOrganizationsBackend) OrganizationsCreate(ctx context.Context, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error) {
+	ctx, done := o.observe(ctx, "OrganizationsCreate")
+	result, err := o.next.OrganizationsCreate(ctx, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedOrganizationsBackend) OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.OrganizationsResponse, error) {
+	ctx, done := o.observe(ctx, "OrganizationsGet")
+	result, err := o.next.OrganizationsGet(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedOrganizationsBackend) OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error) {
+	ctx, done := o.observe(ctx, "OrganizationsUpdate")
+	result, err := o.next.OrganizationsUpdate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedOrganizationsBackend) OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	ctx, done := o.observe(ctx, "OrganizationsDelete")
+	result, err := o.next.OrganizationsDelete(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedAuditBackend struct {
+	next    AuditBackend
+	observe Observer
+}
+
+func (o *observedAuditBackend) AuditEventsCreate(ctx context.Context, event registrytypes.AuditEvent) error {
+	ctx, done := o.observe(ctx, "AuditEventsCreate")
+	err := o.next.AuditEventsCreate(ctx, event)
+	done(err)
+	return err
+}
+
+func (o *observedAuditBackend) AuditEventsList(ctx context.Context, parameters registrytypes.AuditParameters) (*apimodels.AuditEventsListResponse, error) {
+	ctx, done := o.observe(ctx, "AuditEventsList")
+	result, err := o.next.AuditEventsList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedAuditBackend) AuditEventsExport(ctx context.Context, parameters registrytypes.AuditParameters) ([]chain.Record, error) {
+	ctx, done := o.observe(ctx, "AuditEventsExport")
+	result, err := o.next.AuditEventsExport(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedWebhooksBackend struct {
+	next    WebhooksBackend
+	observe Observer
+}
+
+func (o *observedWebhooksBackend) WebhooksList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.WebhooksListResponse, error) {
+	ctx, done := o.observe(ctx, "WebhooksList")
+	result, err := o.next.WebhooksList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhooksCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.WebhooksRequest) (*apimodels.WebhooksResponse, error) {
+	ctx, done := o.observe(ctx, "WebhooksCreate")
+	result, err := o.next.WebhooksCreate(ctx, parameters, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhooksGet(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (*apimodels.WebhooksResponse, error) {
+	ctx, done := o.observe(ctx, "WebhooksGet")
+	result, err := o.next.WebhooksGet(ctx, parameters, webhookID)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhooksUpdate(ctx context.Context, parameters registrytypes.APIParameters, webhookID string, request apimodels.WebhooksRequest) (*apimodels.WebhooksResponse, error) {
+	ctx, done := o.observe(ctx, "WebhooksUpdate")
+	result, err := o.next.WebhooksUpdate(ctx, parameters, webhookID, request)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhooksDelete(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (int, error) {
+	ctx, done := o.observe(ctx, "WebhooksDelete")
+	result, err := o.next.WebhooksDelete(ctx, parameters, webhookID)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhooksSubscribed(ctx context.Context, organization string) ([]registrytypes.Webhook, error) {
+	ctx, done := o.observe(ctx, "WebhooksSubscribed")
+	result, err := o.next.WebhooksSubscribed(ctx, organization)
+	done(err)
+	return result, err
+}
+
+func (o *observedWebhooksBackend) WebhookDeliveriesCreate(ctx context.Context, delivery registrytypes.WebhookDelivery) error {
+	ctx, done := o.observe(ctx, "WebhookDeliveriesCreate")
+	err := o.next.WebhookDeliveriesCreate(ctx, delivery)
+	done(err)
+	return err
+}
+
+func (o *observedWebhooksBackend) WebhookDeliveriesList(ctx context.Context, parameters registrytypes.WebhookDeliveryParameters) (*apimodels.WebhookDeliveriesListResponse, error) {
+	ctx, done := o.observe(ctx, "WebhookDeliveriesList")
+	result, err := o.next.WebhookDeliveriesList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedEventsBackend struct {
+	next    EventsBackend
+	observe Observer
+}
+
+func (o *observedEventsBackend) EventsCreate(ctx context.Context, event events.Event) error {
+	ctx, done := o.observe(ctx, "EventsCreate")
+	err := o.next.EventsCreate(ctx, event)
+	done(err)
+	return err
+}
+
+func (o *observedEventsBackend) EventsList(ctx context.Context, organization string, lastEventID string) ([]events.Event, error) {
+	ctx, done := o.observe(ctx, "EventsList")
+	result, err := o.next.EventsList(ctx, organization, lastEventID)
+	done(err)
+	return result, err
+}
+
+type observedDownloadsBackend struct {
+	next    DownloadsBackend
+	observe Observer
+}
+
+func (o *observedDownloadsBackend) DownloadsRecord(ctx context.Context, download registrytypes.Download) error {
+	ctx, done := o.observe(ctx, "DownloadsRecord")
+	err := o.next.DownloadsRecord(ctx, download)
+	done(err)
+	return err
+}
+
+func (o *observedDownloadsBackend) DownloadsList(ctx context.Context, parameters registrytypes.DownloadParameters) ([]registrytypes.DownloadCounter, error) {
+	ctx, done := o.observe(ctx, "DownloadsList")
+	result, err := o.next.DownloadsList(ctx, parameters)
+	done(err)
+	return result, err
+}
+
+type observedRateLimitsBackend struct {
+	next    RateLimitsBackend
+	observe Observer
+}
+
+func (o *observedRateLimitsBackend) RateLimitsTake(ctx context.Context, key string, limit registrytypes.RateLimit, now time.Time) (registrytypes.RateLimitDecision, error) {
+	ctx, done := o.observe(ctx, "RateLimitsTake")
+	result, err := o.next.RateLimitsTake(ctx, key, limit, now)
+	done(err)
+	return result, err
+}
+
+func (o *observedRateLimitsBackend) RateLimitsPrune(ctx context.Context, before time.Time) error {
+	ctx, done := o.observe(ctx, "RateLimitsPrune")
+	err := o.next.RateLimitsPrune(ctx, before)
+	done(err)
+	return err
+}
+
+type observedCacheInvalidationBackend struct {
+	next    CacheInvalidationBackend
+	observe Observer
+}
+
+func (o *observedCacheInvalidationBackend) CacheInvalidationPublish(ctx context.Context, scope string) error {
+	ctx, done := o.observe(ctx, "CacheInvalidationPublish")
+	err := o.next.CacheInvalidationPublish(ctx, scope)
+	done(err)
+	return err
+}
+
+// CacheInvalidationListen is not observed, it runs for as long as the registry.
+func (o *observedCacheInvalidationBackend) CacheInvalidationListen(ctx context.Context, invalidate func(scope string)) error {
+	return o.next.CacheInvalidationListen(ctx, invalidate)
+}
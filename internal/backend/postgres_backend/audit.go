@@ -0,0 +1,107 @@
+package postgres_backend
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/audit/chain"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"time"
+)
+
+var _ backend.AuditBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) AuditEventsCreate(ctx context.Context, event registrytypes.AuditEvent) error {
+	value := &AuditEvent{
+		ID:           uuid.New().String(),
+		Organization: event.Organization,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		CreatedAt:    event.Timestamp.UTC(),
+	}
+
+	return auditEventInsert(ctx, p.db, value)
+}
+
+func (p *PostgresBackend) AuditEventsList(ctx context.Context, parameters registrytypes.AuditParameters) (*models.AuditEventsListResponse, error) {
+	offset := (parameters.PageNumber - 1) * parameters.PageSize
+	events, pagination, err := auditEventsList(ctx, p.db, parameters.Organization, parameters.Since, parameters.Until, parameters.PageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.AuditEventsListResponse{
+		Data: []models.AuditEventsDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, pagination.TotalCount),
+		},
+	}
+
+	for _, event := range events {
+		resp.Data = append(resp.Data, auditEventData(event))
+	}
+
+	return resp, nil
+}
+
+func (p *PostgresBackend) AuditEventsExport(ctx context.Context, parameters registrytypes.AuditParameters) ([]chain.Record, error) {
+	events, err := auditEventsChain(ctx, p.db, parameters.Organization, parameters.Since, parameters.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []chain.Record
+	for _, event := range events {
+		records = append(records, auditRecord(event))
+	}
+
+	return records, nil
+}
+
+func linkAuditEvent(event *AuditEvent, headSequence int64, headHash string) {
+	record := auditRecord(*event)
+	chain.Link(&record, headSequence, headHash)
+
+	event.Sequence = record.Sequence
+	event.PrevHash = record.PrevHash
+	event.Hash = record.Hash
+}
+
+func auditRecord(event AuditEvent) chain.Record {
+	return chain.Record{
+		Sequence:     event.Sequence,
+		ID:           event.ID,
+		Timestamp:    event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Organization: event.Organization,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		Target:       event.Target,
+		SourceIP:     event.SourceIP,
+		Outcome:      event.Outcome,
+		StatusCode:   event.StatusCode,
+		PrevHash:     event.PrevHash,
+		Hash:         event.Hash,
+	}
+}
+
+func auditEventData(event AuditEvent) models.AuditEventsDataResponse {
+	return models.AuditEventsDataResponse{
+		ID:   event.ID,
+		Type: "audit-events",
+		Attributes: models.AuditEventsAttributesResponse{
+			Timestamp:    event.CreatedAt.UTC().Format(time.RFC3339Nano),
+			Organization: event.Organization,
+			Actor:        event.Actor,
+			Action:       event.Action,
+			Target:       event.Target,
+			SourceIP:     event.SourceIP,
+			Outcome:      event.Outcome,
+			StatusCode:   event.StatusCode,
+		},
+	}
+}
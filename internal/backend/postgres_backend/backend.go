@@ -7,7 +7,6 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go-terraform-registry/internal/backend"
 	"go-terraform-registry/internal/config"
-	"os"
 )
 
 var _ backend.BackendLifecycle = &PostgresBackend{}
@@ -24,18 +23,27 @@ func NewPostgresBackend(_ context.Context, config config.RegistryConfig) (*backe
 	}
 
 	return &backend.Backend{
-		BackendLifecycle:        b,
-		RegistryBackend:         b,
-		ProvidersBackend:        b,
-		ProviderVersionsBackend: b,
-		ModulesBackend:          b,
-		ModuleVersionsBackend:   b,
-		GPGKeysBackend:          b,
+		BackendLifecycle:         b,
+		RegistryBackend:          b,
+		ProvidersBackend:         b,
+		ProviderVersionsBackend:  b,
+		ModulesBackend:           b,
+		ModuleVersionsBackend:    b,
+		GPGKeysBackend:           b,
+		NamespacesBackend:        b,
+		SharesBackend:            b,
+		OrganizationsBackend:     b,
+		AuditBackend:             b,
+		WebhooksBackend:          b,
+		EventsBackend:            b,
+		DownloadsBackend:         b,
+		RateLimitsBackend:        b,
+		CacheInvalidationBackend: b,
 	}, nil
 }
 
 func (p *PostgresBackend) Configure(ctx context.Context) error {
-	connectionString := os.Getenv("DATABASE_URL")
+	connectionString := p.Config.Postgres.DatabaseURL
 	pgxConfig, err := pgxpool.ParseConfig(connectionString)
 
 	// Future use for refreshing credentials (IAM)
@@ -57,3 +65,7 @@ func (p *PostgresBackend) Close(_ context.Context) error {
 
 	return nil
 }
+
+func (p *PostgresBackend) HealthCheck(ctx context.Context) error {
+	return p.db.Ping(ctx)
+}
@@ -0,0 +1,35 @@
+package postgres_backend
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	"log/slog"
+	"time"
+)
+
+var _ backend.CacheInvalidationBackend = &PostgresBackend{}
+
+const cacheInvalidationRetry = 5 * time.Second
+
+func (p *PostgresBackend) CacheInvalidationPublish(ctx context.Context, scope string) error {
+	return cacheInvalidationNotify(ctx, p.db, scope)
+}
+
+// CacheInvalidationListen relays notifications from every replica, listening
+// again after losing the connection. Notifications sent in between are lost,
+// so everything is invalidated whenever listening starts.
+func (p *PostgresBackend) CacheInvalidationListen(ctx context.Context, invalidate func(scope string)) error {
+	for {
+		err := cacheInvalidationListen(ctx, p.db, func() { invalidate("") }, invalidate)
+		if ctx.Err() != nil {
+			return nil
+		}
+		slog.WarnContext(ctx, "Lost cache invalidation listener, listening again", "error", err, "retry", cacheInvalidationRetry.String())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cacheInvalidationRetry):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package postgres_backend
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+)
+
+var _ backend.DownloadsBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) DownloadsRecord(ctx context.Context, download registrytypes.Download) error {
+	var counters []DownloadCounter
+	for _, counter := range backend.DownloadCounters(download) {
+		counters = append(counters, DownloadCounter{
+			Kind:      download.Kind,
+			Namespace: download.Namespace,
+			Name:      download.Name,
+			Provider:  download.Provider,
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+		})
+	}
+
+	return downloadCountersIncrement(ctx, p.db, counters)
+}
+
+func (p *PostgresBackend) DownloadsList(ctx context.Context, parameters registrytypes.DownloadParameters) ([]registrytypes.DownloadCounter, error) {
+	counters, err := downloadCountersList(ctx, p.db, parameters.Kind, parameters.Namespace, parameters.Name, parameters.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []registrytypes.DownloadCounter
+	for _, counter := range counters {
+		result = append(result, registrytypes.DownloadCounter{
+			Version:   counter.Version,
+			Dimension: counter.Dimension,
+			Key:       counter.Key,
+			Count:     counter.Count,
+		})
+	}
+
+	return result, nil
+}
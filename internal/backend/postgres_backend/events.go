@@ -0,0 +1,46 @@
+package postgres_backend
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/events"
+)
+
+var _ backend.EventsBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) EventsCreate(ctx context.Context, event events.Event) error {
+	value := &RegistryEvent{
+		ID:           event.ID,
+		Organization: event.Organization,
+		Type:         event.Type,
+		Subject:      event.Subject,
+		Data:         event.Data,
+		CreatedAt:    event.Timestamp,
+	}
+	if value.Data == nil {
+		value.Data = map[string]string{}
+	}
+
+	return registryEventInsert(ctx, p.db, value, backend.EventLogRetention)
+}
+
+func (p *PostgresBackend) EventsList(ctx context.Context, organization string, lastEventID string) ([]events.Event, error) {
+	logged, err := registryEventsList(ctx, p.db, organization, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []events.Event
+	for _, event := range logged {
+		result = append(result, events.Event{
+			ID:           event.ID,
+			Type:         event.Type,
+			Organization: event.Organization,
+			Subject:      event.Subject,
+			Timestamp:    event.CreatedAt.UTC(),
+			Data:         event.Data,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package postgres_backend
+
+import (
+	"context"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"time"
+)
+
+var _ backend.NamespacesBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.NamespacesResponse, error) {
+	ns, err := namespaceSelect(ctx, p.db, parameters.Organization, parameters.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		ns = &Namespace{
+			Organization:    parameters.Organization,
+			Namespace:       parameters.Namespace,
+			PublicModules:   []string{},
+			PublicProviders: []string{},
+		}
+	}
+
+	return namespaceResponse(ns), nil
+}
+
+func (p *PostgresBackend) NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.NamespacesRequest) (*models.NamespacesResponse, error) {
+	ns, err := namespaceSelect(ctx, p.db, parameters.Organization, parameters.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		ns = &Namespace{
+			Organization:    parameters.Organization,
+			Namespace:       parameters.Namespace,
+			PublicModules:   []string{},
+			PublicProviders: []string{},
+		}
+	}
+
+	attributes := request.Data.Attributes
+	if attributes.Public != nil {
+		ns.Public = *attributes.Public
+	}
+	if attributes.PublicModules != nil {
+		ns.PublicModules = *attributes.PublicModules
+	}
+	if attributes.PublicProviders != nil {
+		ns.PublicProviders = *attributes.PublicProviders
+	}
+
+	err = namespaceUpsert(ctx, p.db, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResponse(ns), nil
+}
+
+func namespaceResponse(ns *Namespace) *models.NamespacesResponse {
+	resp := &models.NamespacesResponse{
+		Data: models.NamespacesDataResponse{
+			ID:   ns.ID,
+			Type: "registry-namespaces",
+			Attributes: models.NamespacesAttributesResponse{
+				Name:            ns.Namespace,
+				Public:          ns.Public,
+				PublicModules:   ns.PublicModules,
+				PublicProviders: ns.PublicProviders,
+			},
+		},
+	}
+
+	if !ns.UpdatedAt.IsZero() {
+		resp.Data.Attributes.UpdatedAt = ns.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return resp
+}
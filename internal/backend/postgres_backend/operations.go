@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
 )
 
 func WithTransaction(ctx context.Context, db *pgxpool.Pool, fn func(pgx.Tx) error) error {
@@ -492,3 +493,808 @@ func providerVersionPlatformInsert(ctx context.Context, db *pgxpool.Pool, value
 		return err
 	})
 }
+
+func namespaceSelect(ctx context.Context, db *pgxpool.Pool, organization string, namespace string) (*Namespace, error) {
+	query := `
+		SELECT namespace_id, organization, namespace, public, public_modules, public_providers, updated_at
+		FROM namespaces
+		WHERE organization = $1 AND namespace = $2;
+	`
+
+	row := db.QueryRow(ctx, query, organization, namespace)
+
+	var ns Namespace
+	err := row.Scan(
+		&ns.ID,
+		&ns.Organization,
+		&ns.Namespace,
+		&ns.Public,
+		&ns.PublicModules,
+		&ns.PublicProviders,
+		&ns.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &ns, nil
+}
+
+func namespaceUpsert(ctx context.Context, db *pgxpool.Pool, value *Namespace) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO namespaces (organization, namespace, public, public_modules, public_providers)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT ON CONSTRAINT unique_namespace_identity
+			DO UPDATE SET public = EXCLUDED.public, public_modules = EXCLUDED.public_modules, public_providers = EXCLUDED.public_providers, updated_at = now()
+			RETURNING namespace_id, updated_at;
+	`
+		return tx.QueryRow(ctx, query, value.Organization, value.Namespace, value.Public, value.PublicModules, value.PublicProviders).Scan(&value.ID, &value.UpdatedAt)
+	})
+}
+
+func sharesInsert(ctx context.Context, db *pgxpool.Pool, value *Share) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO shares (organization, grantee, resource_type, namespace, name, provider)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING share_id, created_at;
+	`
+		err := tx.QueryRow(ctx, query, value.Organization, value.Grantee, value.ResourceType, value.Namespace, value.Name, value.Provider).Scan(&value.ID, &value.CreatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				if pgErr.ConstraintName == "unique_share_identity" {
+					return fmt.Errorf("share already exists")
+				}
+			}
+		}
+		return err
+	})
+}
+
+func sharesList(ctx context.Context, db *pgxpool.Pool, organization string) ([]Share, error) {
+	query := `
+		SELECT share_id, organization, grantee, resource_type, namespace, name, provider, created_at
+		FROM shares
+		WHERE organization = $1
+		ORDER BY created_at;
+	`
+
+	rows, err := db.Query(ctx, query, organization)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []Share
+	for rows.Next() {
+		var share Share
+		err := rows.Scan(
+			&share.ID,
+			&share.Organization,
+			&share.Grantee,
+			&share.ResourceType,
+			&share.Namespace,
+			&share.Name,
+			&share.Provider,
+			&share.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+func sharesDelete(ctx context.Context, db *pgxpool.Pool, organization string, shareID string) (int64, error) {
+	query := `
+		DELETE FROM shares
+		WHERE organization = $1 AND share_id::text = $2;
+	`
+
+	tag, err := db.Exec(ctx, query, organization, shareID)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func sharesGranted(ctx context.Context, db *pgxpool.Pool, organization string, resourceType string, namespace string, name string, provider string, grantees []string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM shares
+			WHERE lower(organization) = lower($1) AND resource_type = $2 AND lower(namespace) = lower($3)
+			  AND lower(name) = lower($4) AND lower(provider) = lower($5) AND lower(grantee) = ANY($6)
+		);
+	`
+
+	var granted bool
+	err := db.QueryRow(ctx, query, organization, resourceType, namespace, name, provider, grantees).Scan(&granted)
+
+	return granted, err
+}
+
+func organizationsList(ctx context.Context, db *pgxpool.Pool) ([]Organization, error) {
+	query := `
+		SELECT organization_id, name, email, default_registry, created_at
+		FROM organizations
+		ORDER BY lower(name);
+	`
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizations []Organization
+	for rows.Next() {
+		var organization Organization
+		err := rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.Email,
+			&organization.DefaultRegistry,
+			&organization.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, rows.Err()
+}
+
+func organizationSelect(ctx context.Context, db *pgxpool.Pool, name string) (*Organization, error) {
+	query := `
+		SELECT organization_id, name, email, default_registry, created_at
+		FROM organizations
+		WHERE lower(name) = lower($1);
+	`
+
+	row := db.QueryRow(ctx, query, name)
+
+	var organization Organization
+	err := row.Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.Email,
+		&organization.DefaultRegistry,
+		&organization.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &organization, nil
+}
+
+func organizationInsert(ctx context.Context, db *pgxpool.Pool, value *Organization) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO organizations (name, email, default_registry)
+			VALUES ($1, $2, $3)
+			RETURNING organization_id, created_at;
+	`
+		err := tx.QueryRow(ctx, query, value.Name, value.Email, value.DefaultRegistry).Scan(&value.ID, &value.CreatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				if pgErr.ConstraintName == "unique_organization_name" || pgErr.ConstraintName == "idx_organizations_name" {
+					return fmt.Errorf("organization already exists")
+				}
+			}
+		}
+		return err
+	})
+}
+
+// organizationUpdate renames namespaces, shares, GPG keys and webhooks along
+// with the organization and drops its event log and download counters, which
+// only outlive deleted providers and modules. Providers and modules are
+// refused since their storage paths and registry addresses contain the
+// organization name.
+func organizationUpdate(ctx context.Context, db *pgxpool.Pool, name string, value *Organization) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		if value.Name != name {
+			var published bool
+			query := `
+				SELECT EXISTS (SELECT 1 FROM providers WHERE lower(organization) = lower($1))
+				    OR EXISTS (SELECT 1 FROM modules WHERE lower(organization) = lower($1));
+		`
+			err := tx.QueryRow(ctx, query, name).Scan(&published)
+			if err != nil {
+				return err
+			}
+			if published {
+				return fmt.Errorf("organization with providers or modules cannot be renamed")
+			}
+
+			renames := []string{
+				`UPDATE namespaces SET organization = $2, namespace = CASE WHEN lower(namespace) = lower($1) THEN $2 ELSE namespace END WHERE lower(organization) = lower($1);`,
+				`UPDATE shares SET organization = $2, namespace = CASE WHEN lower(namespace) = lower($1) THEN $2 ELSE namespace END WHERE lower(organization) = lower($1);`,
+				`UPDATE shares SET grantee = $2 WHERE lower(grantee) = lower($1);`,
+				`UPDATE gpg_keys SET namespace = $2, updated_at = now() WHERE lower(namespace) = lower($1);`,
+				`UPDATE webhooks SET organization = $2, updated_at = now() WHERE lower(organization) = lower($1);`,
+			}
+			for _, rename := range renames {
+				_, err := tx.Exec(ctx, rename, name, value.Name)
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, drop := range []string{
+				`DELETE FROM registry_events WHERE lower(organization) = lower($1);`,
+				`DELETE FROM download_counters WHERE lower(namespace) = lower($1);`,
+			} {
+				_, err = tx.Exec(ctx, drop, name)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		query := `
+			UPDATE organizations
+			SET name = $2, email = $3, default_registry = $4, updated_at = now()
+			WHERE lower(name) = lower($1);
+	`
+		_, err := tx.Exec(ctx, query, name, value.Name, value.Email, value.DefaultRegistry)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				if pgErr.ConstraintName == "unique_organization_name" || pgErr.ConstraintName == "idx_organizations_name" {
+					return fmt.Errorf("organization already exists")
+				}
+			}
+		}
+		return err
+	})
+}
+
+// organizationDelete removes the organization and everything it owns. Provider
+// versions, platforms and module versions follow through ON DELETE CASCADE.
+func organizationDelete(ctx context.Context, db *pgxpool.Pool, name string) (int64, error) {
+	var deleted int64
+	err := WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		cascades := []string{
+			`DELETE FROM providers WHERE lower(organization) = lower($1);`,
+			`DELETE FROM modules WHERE lower(organization) = lower($1);`,
+			`DELETE FROM namespaces WHERE lower(organization) = lower($1);`,
+			`DELETE FROM shares WHERE lower(organization) = lower($1) OR lower(grantee) = lower($1);`,
+			`DELETE FROM gpg_keys WHERE lower(namespace) = lower($1) AND NOT EXISTS (SELECT 1 FROM provider_versions pv WHERE pv.gpgkey_id = gpg_keys.gpgkey_id);`,
+			`DELETE FROM webhooks WHERE lower(organization) = lower($1);`,
+			`DELETE FROM registry_events WHERE lower(organization) = lower($1);`,
+			`DELETE FROM download_counters WHERE lower(namespace) = lower($1);`,
+		}
+		for _, cascade := range cascades {
+			_, err := tx.Exec(ctx, cascade, name)
+			if err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx, `DELETE FROM organizations WHERE lower(name) = lower($1);`, name)
+		if err != nil {
+			return err
+		}
+		deleted = tag.RowsAffected()
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// auditEventInsert appends value to the hash chain of its organization. The
+// advisory lock serializes writers so each sequence is linked to its head.
+func auditEventInsert(ctx context.Context, db *pgxpool.Pool, value *AuditEvent) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext(lower($1)));", value.Organization)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			SELECT sequence, hash
+			FROM audit_events
+			WHERE lower(organization) = lower($1) AND sequence IS NOT NULL
+			ORDER BY sequence DESC
+			LIMIT 1;
+	`
+		var headSequence int64
+		var headHash string
+		err = tx.QueryRow(ctx, query, value.Organization).Scan(&headSequence, &headHash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		linkAuditEvent(value, headSequence, headHash)
+
+		query = `
+			INSERT INTO audit_events (audit_event_id, organization, actor, action, target, source_ip, outcome, status_code, created_at, sequence, prev_hash, hash)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+	`
+		_, err = tx.Exec(ctx, query, value.ID, value.Organization, value.Actor, value.Action, value.Target, value.SourceIP, value.Outcome, value.StatusCode, value.CreatedAt, value.Sequence, value.PrevHash, value.Hash)
+		return err
+	})
+}
+
+// auditEventsChain returns the chained events of an organization in sequence
+// order. Events recorded before the chain was introduced have no sequence.
+func auditEventsChain(ctx context.Context, db *pgxpool.Pool, organization string, since *time.Time, until *time.Time) ([]AuditEvent, error) {
+	query := `
+		SELECT audit_event_id, organization, actor, action, target, source_ip, outcome, status_code, created_at, sequence, prev_hash, hash
+		FROM audit_events
+		WHERE lower(organization) = lower($1)
+		  AND sequence IS NOT NULL
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY sequence;
+	`
+
+	rows, err := db.Query(ctx, query, organization, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []AuditEvent
+	for rows.Next() {
+		var event AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.Organization,
+			&event.Actor,
+			&event.Action,
+			&event.Target,
+			&event.SourceIP,
+			&event.Outcome,
+			&event.StatusCode,
+			&event.CreatedAt,
+			&event.Sequence,
+			&event.PrevHash,
+			&event.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func auditEventsList(ctx context.Context, db *pgxpool.Pool, organization string, since *time.Time, until *time.Time, limit int, offset int) ([]AuditEvent, *Pagination, error) {
+	filter := `
+		FROM audit_events
+		WHERE lower(organization) = lower($1)
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+	`
+
+	var pagination Pagination
+	err := db.QueryRow(ctx, "SELECT COUNT(*) "+filter, organization, since, until).Scan(&pagination.TotalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT audit_event_id, organization, actor, action, target, source_ip, outcome, status_code, created_at
+	` + filter + `
+		ORDER BY created_at, audit_event_id
+		LIMIT $4 OFFSET $5;
+	`
+
+	rows, err := db.Query(ctx, query, organization, since, until, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var events []AuditEvent
+	for rows.Next() {
+		var event AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.Organization,
+			&event.Actor,
+			&event.Action,
+			&event.Target,
+			&event.SourceIP,
+			&event.Outcome,
+			&event.StatusCode,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, &pagination, rows.Err()
+}
+
+func webhookInsert(ctx context.Context, db *pgxpool.Pool, value *Webhook) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO webhooks (organization, url, events, secret, enabled)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING webhook_id, created_at, updated_at;
+	`
+		return tx.QueryRow(ctx, query, value.Organization, value.URL, value.Events, value.Secret, value.Enabled).Scan(&value.ID, &value.CreatedAt, &value.UpdatedAt)
+	})
+}
+
+func webhooksList(ctx context.Context, db *pgxpool.Pool, organization string) ([]Webhook, error) {
+	query := `
+		SELECT webhook_id, organization, url, events, secret, enabled, created_at, updated_at
+		FROM webhooks
+		WHERE lower(organization) = lower($1)
+		ORDER BY created_at;
+	`
+
+	rows, err := db.Query(ctx, query, organization)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		var webhook Webhook
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.Organization,
+			&webhook.URL,
+			&webhook.Events,
+			&webhook.Secret,
+			&webhook.Enabled,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func webhookSelect(ctx context.Context, db *pgxpool.Pool, organization string, webhookID string) (*Webhook, error) {
+	query := `
+		SELECT webhook_id, organization, url, events, secret, enabled, created_at, updated_at
+		FROM webhooks
+		WHERE lower(organization) = lower($1) AND webhook_id::text = $2;
+	`
+
+	var webhook Webhook
+	err := db.QueryRow(ctx, query, organization, webhookID).Scan(
+		&webhook.ID,
+		&webhook.Organization,
+		&webhook.URL,
+		&webhook.Events,
+		&webhook.Secret,
+		&webhook.Enabled,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func webhookUpdate(ctx context.Context, db *pgxpool.Pool, value *Webhook) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			UPDATE webhooks
+			SET url = $2, events = $3, secret = $4, enabled = $5, updated_at = now()
+			WHERE webhook_id = $1
+			RETURNING updated_at;
+	`
+		return tx.QueryRow(ctx, query, value.ID, value.URL, value.Events, value.Secret, value.Enabled).Scan(&value.UpdatedAt)
+	})
+}
+
+func webhookDelete(ctx context.Context, db *pgxpool.Pool, organization string, webhookID string) (int64, error) {
+	query := `
+		DELETE FROM webhooks
+		WHERE lower(organization) = lower($1) AND webhook_id::text = $2;
+	`
+
+	tag, err := db.Exec(ctx, query, organization, webhookID)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// webhookDeliveryInsert records a delivery attempt and prunes attempts beyond
+// the retention of the webhook.
+func webhookDeliveryInsert(ctx context.Context, db *pgxpool.Pool, value *WebhookDelivery, retention int) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, attempt, status_code, error, duration_ms, success, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING delivery_id;
+	`
+		err := tx.QueryRow(ctx, query, value.WebhookID, value.EventID, value.EventType, value.Attempt, value.StatusCode, value.Error, value.DurationMS, value.Success, value.CreatedAt).Scan(&value.ID)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM webhook_deliveries
+			WHERE webhook_id = $1 AND delivery_id NOT IN (
+				SELECT delivery_id FROM webhook_deliveries
+				WHERE webhook_id = $1
+				ORDER BY created_at DESC
+				LIMIT $2
+			);
+	`
+		_, err = tx.Exec(ctx, query, value.WebhookID, retention)
+		return err
+	})
+}
+
+func webhookDeliveriesList(ctx context.Context, db *pgxpool.Pool, webhookID string, limit int, offset int) ([]WebhookDelivery, *Pagination, error) {
+	var pagination Pagination
+	err := db.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id::text = $1;", webhookID).Scan(&pagination.TotalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT delivery_id, webhook_id, event_id, event_type, attempt, status_code, error, duration_ms, success, created_at
+		FROM webhook_deliveries
+		WHERE webhook_id::text = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3;
+	`
+
+	rows, err := db.Query(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var delivery WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.DurationMS,
+			&delivery.Success,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, &pagination, rows.Err()
+}
+
+func registryEventInsert(ctx context.Context, db *pgxpool.Pool, value *RegistryEvent, retention int) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO registry_events (event_id, organization, event_type, subject, data, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING sequence;
+	`
+		err := tx.QueryRow(ctx, query, value.ID, value.Organization, value.Type, value.Subject, value.Data, value.CreatedAt).Scan(&value.Sequence)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM registry_events
+			WHERE lower(organization) = lower($1) AND sequence NOT IN (
+				SELECT sequence FROM registry_events
+				WHERE lower(organization) = lower($1)
+				ORDER BY sequence DESC
+				LIMIT $2
+			);
+	`
+		_, err = tx.Exec(ctx, query, value.Organization, retention)
+		return err
+	})
+}
+
+// registryEventsList returns the events of an organization after the given
+// event, or all of them when it is not logged.
+func registryEventsList(ctx context.Context, db *pgxpool.Pool, organization string, lastEventID string) ([]RegistryEvent, error) {
+	query := `
+		SELECT sequence, event_id, organization, event_type, subject, data, created_at
+		FROM registry_events
+		WHERE lower(organization) = lower($1) AND sequence > COALESCE(
+			(SELECT sequence FROM registry_events WHERE lower(organization) = lower($1) AND event_id = $2), 0)
+		ORDER BY sequence;
+	`
+
+	rows, err := db.Query(ctx, query, organization, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []RegistryEvent
+	for rows.Next() {
+		var event RegistryEvent
+		err := rows.Scan(
+			&event.Sequence,
+			&event.ID,
+			&event.Organization,
+			&event.Type,
+			&event.Subject,
+			&event.Data,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func downloadCountersIncrement(ctx context.Context, db *pgxpool.Pool, counters []DownloadCounter) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO download_counters (kind, namespace, name, provider, version, dimension, key, count)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, 1)
+			ON CONFLICT (kind, lower(namespace), lower(name), lower(provider), version, dimension, key)
+			DO UPDATE SET count = download_counters.count + 1;
+	`
+		for _, counter := range counters {
+			_, err := tx.Exec(ctx, query, counter.Kind, counter.Namespace, counter.Name, counter.Provider, counter.Version, counter.Dimension, counter.Key)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func downloadCountersList(ctx context.Context, db *pgxpool.Pool, kind string, namespace string, name string, provider string) ([]DownloadCounter, error) {
+	query := `
+		SELECT kind, namespace, name, provider, version, dimension, key, count
+		FROM download_counters
+		WHERE kind = $1 AND lower(namespace) = lower($2) AND lower(name) = lower($3) AND lower(provider) = lower($4);
+	`
+
+	rows, err := db.Query(ctx, query, kind, namespace, name, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counters []DownloadCounter
+	for rows.Next() {
+		var counter DownloadCounter
+		err := rows.Scan(
+			&counter.Kind,
+			&counter.Namespace,
+			&counter.Name,
+			&counter.Provider,
+			&counter.Version,
+			&counter.Dimension,
+			&counter.Key,
+			&counter.Count,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		counters = append(counters, counter)
+	}
+
+	return counters, rows.Err()
+}
+
+// rateLimitTake locks the bucket of key for the transaction, inserting it
+// full when missing, and stores the bucket returned by take.
+func rateLimitTake(ctx context.Context, db *pgxpool.Pool, key string, burst float64, take func(RateLimitBucket) RateLimitBucket) error {
+	return WithTransaction(ctx, db, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+			VALUES ($1, $2, now())
+			ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+			RETURNING key, tokens, updated_at;
+		`
+
+		var bucket RateLimitBucket
+		err := tx.QueryRow(ctx, query, key, burst).Scan(&bucket.Key, &bucket.Tokens, &bucket.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		bucket = take(bucket)
+
+		query = `
+			UPDATE rate_limit_buckets
+			SET tokens = $2, updated_at = $3
+			WHERE key = $1;
+		`
+		_, err = tx.Exec(ctx, query, bucket.Key, bucket.Tokens, bucket.UpdatedAt)
+		return err
+	})
+}
+
+func rateLimitPrune(ctx context.Context, db *pgxpool.Pool, before time.Time) error {
+	query := `
+		DELETE FROM rate_limit_buckets
+		WHERE updated_at < $1;
+	`
+
+	_, err := db.Exec(ctx, query, before)
+	return err
+}
+
+const cacheInvalidationChannel = "registry_cache_invalidation"
+
+func cacheInvalidationNotify(ctx context.Context, db *pgxpool.Pool, scope string) error {
+	_, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", cacheInvalidationChannel, scope)
+	return err
+}
+
+// cacheInvalidationListen listens on a connection taken out of the pool, so it
+// is never handed back while still listening. listening is called once the
+// connection listens and notified with the payload of every notification.
+func cacheInvalidationListen(ctx context.Context, db *pgxpool.Pool, listening func(), notified func(string)) error {
+	pooled, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+cacheInvalidationChannel); err != nil {
+		return err
+	}
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		notified(notification.Payload)
+	}
+}
@@ -0,0 +1,116 @@
+package postgres_backend
+
+import (
+	"context"
+	"fmt"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"time"
+)
+
+var _ backend.OrganizationsBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) OrganizationsList(ctx context.Context) (*models.OrganizationsListResponse, error) {
+	organizations, err := organizationsList(ctx, p.db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.OrganizationsListResponse{
+		Data: []models.OrganizationsDataResponse{},
+	}
+
+	for _, organization := range organizations {
+		resp.Data = append(resp.Data, organizationResponse(organization).Data)
+	}
+
+	return resp, nil
+}
+
+func (p *PostgresBackend) OrganizationsCreate(ctx context.Context, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	organization := &Organization{
+		DefaultRegistry: "private",
+	}
+	applyOrganizationAttributes(organization, request.Data.Attributes)
+
+	err := organizationInsert(ctx, p.db, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (p *PostgresBackend) OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*models.OrganizationsResponse, error) {
+	organization, err := organizationSelect(ctx, p.db, parameters.Organization)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, nil
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (p *PostgresBackend) OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request models.OrganizationsRequest) (*models.OrganizationsResponse, error) {
+	organization, err := organizationSelect(ctx, p.db, parameters.Organization)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, fmt.Errorf("organization not found")
+	}
+
+	name := organization.Name
+	applyOrganizationAttributes(organization, request.Data.Attributes)
+
+	err = organizationUpdate(ctx, p.db, name, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return organizationResponse(*organization), nil
+}
+
+func (p *PostgresBackend) OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	deleted, err := organizationDelete(ctx, p.db, parameters.Organization)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf("organization not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func applyOrganizationAttributes(organization *Organization, attributes models.OrganizationsAttributesRequest) {
+	if attributes.Name != nil {
+		organization.Name = *attributes.Name
+	}
+	if attributes.Email != nil {
+		organization.Email = *attributes.Email
+	}
+	if attributes.DefaultRegistry != nil {
+		organization.DefaultRegistry = *attributes.DefaultRegistry
+	}
+}
+
+func organizationResponse(organization Organization) *models.OrganizationsResponse {
+	return &models.OrganizationsResponse{
+		Data: models.OrganizationsDataResponse{
+			ID:   organization.Name,
+			Type: "organizations",
+			Attributes: models.OrganizationsAttributesResponse{
+				Name:            organization.Name,
+				Email:           organization.Email,
+				DefaultRegistry: organization.DefaultRegistry,
+				ExternalID:      organization.ID,
+				CreatedAt:       organization.CreatedAt.Format(time.RFC3339),
+			},
+		},
+	}
+}
@@ -83,7 +83,7 @@ func (p *PostgresBackend) ProviderVersionsCreate(ctx context.Context, parameters
 		return nil, err
 	}
 	if gpgKey == nil {
-		return nil, fmt.Errorf("gpg key not found")
+		return nil, backend.ErrSigningKeyNotFound
 	}
 
 	pv := &ProviderVersion{
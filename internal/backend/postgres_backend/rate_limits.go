@@ -0,0 +1,37 @@
+package postgres_backend
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"time"
+)
+
+var _ backend.RateLimitsBackend = &PostgresBackend{}
+
+// RateLimitsTake refills and takes from the bucket while its row is locked, so
+// replicas sharing the database draw from the same bucket.
+func (p *PostgresBackend) RateLimitsTake(ctx context.Context, key string, limit registrytypes.RateLimit, now time.Time) (registrytypes.RateLimitDecision, error) {
+	var decision registrytypes.RateLimitDecision
+
+	err := rateLimitTake(ctx, p.db, key, float64(limit.Burst), func(bucket RateLimitBucket) RateLimitBucket {
+		var taken registrytypes.RateLimitBucket
+		taken, decision = backend.TakeRateLimitToken(registrytypes.RateLimitBucket{
+			Tokens:  bucket.Tokens,
+			Updated: bucket.UpdatedAt,
+		}, limit, now)
+
+		bucket.Tokens = taken.Tokens
+		bucket.UpdatedAt = taken.Updated
+		return bucket
+	})
+	if err != nil {
+		return registrytypes.RateLimitDecision{}, err
+	}
+
+	return decision, nil
+}
+
+func (p *PostgresBackend) RateLimitsPrune(ctx context.Context, before time.Time) error {
+	return rateLimitPrune(ctx, p.db, before)
+}
@@ -0,0 +1,96 @@
+package postgres_backend
+
+import (
+	"context"
+	"fmt"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var _ backend.SharesBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*models.SharesListResponse, error) {
+	shares, err := sharesList(ctx, p.db, parameters.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.SharesListResponse{
+		Data: []models.SharesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.PaginationMeta{
+				PageSize:    len(shares),
+				CurrentPage: 1,
+				TotalPages:  1,
+				TotalCount:  len(shares),
+			},
+		},
+	}
+
+	for _, share := range shares {
+		resp.Data = append(resp.Data, shareData(share))
+	}
+
+	return resp, nil
+}
+
+func (p *PostgresBackend) SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.SharesRequest) (*models.SharesResponse, error) {
+	share := &Share{
+		Organization: parameters.Organization,
+		Grantee:      request.Data.Attributes.GranteeOrganization,
+		ResourceType: request.Data.Attributes.ResourceType,
+		Namespace:    request.Data.Attributes.Namespace,
+		Name:         request.Data.Attributes.Name,
+		Provider:     request.Data.Attributes.Provider,
+	}
+
+	err := sharesInsert(ctx, p.db, share)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharesResponse{
+		Data: shareData(*share),
+	}, nil
+}
+
+func (p *PostgresBackend) SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error) {
+	deleted, err := sharesDelete(ctx, p.db, parameters.Organization, shareID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf("share not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (p *PostgresBackend) SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error) {
+	var grantees []string
+	for _, grantee := range parameters.Grantees {
+		grantees = append(grantees, strings.ToLower(grantee))
+	}
+
+	return sharesGranted(ctx, p.db, parameters.Organization, parameters.ResourceType, parameters.Namespace, parameters.Name, parameters.Provider, grantees)
+}
+
+func shareData(share Share) models.SharesDataResponse {
+	return models.SharesDataResponse{
+		ID:   share.ID,
+		Type: "registry-shares",
+		Attributes: models.SharesAttributesResponse{
+			Organization:        share.Organization,
+			GranteeOrganization: share.Grantee,
+			ResourceType:        share.ResourceType,
+			Namespace:           share.Namespace,
+			Name:                share.Name,
+			Provider:            share.Provider,
+			CreatedAt:           share.CreatedAt.Format(time.RFC3339),
+		},
+	}
+}
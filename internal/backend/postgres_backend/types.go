@@ -1,5 +1,7 @@
 package postgres_backend
 
+import "time"
+
 type GPGKey struct {
 	Namespace  string `json:"namespace"`
 	KeyID      string `json:"key_id"`
@@ -92,3 +94,98 @@ type ModuleReleaseVersionInfo struct {
 type Pagination struct {
 	TotalCount int `json:"total-count"`
 }
+
+type Namespace struct {
+	ID              string    `json:"id"`
+	Organization    string    `json:"organization"`
+	Namespace       string    `json:"namespace"`
+	Public          bool      `json:"public"`
+	PublicModules   []string  `json:"public_modules"`
+	PublicProviders []string  `json:"public_providers"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type Share struct {
+	ID           string    `json:"id"`
+	Organization string    `json:"organization"`
+	Grantee      string    `json:"grantee"`
+	ResourceType string    `json:"resource_type"`
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	Provider     string    `json:"provider"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Organization struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	DefaultRegistry string    `json:"default_registry"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID           string    `json:"id"`
+	Organization string    `json:"organization"`
+	Actor        string    `json:"actor"`
+	Action       string    `json:"action"`
+	Target       string    `json:"target"`
+	SourceIP     string    `json:"source_ip"`
+	Outcome      string    `json:"outcome"`
+	StatusCode   int       `json:"status_code"`
+	CreatedAt    time.Time `json:"created_at"`
+	Sequence     int64     `json:"sequence"`
+	PrevHash     string    `json:"prev_hash"`
+	Hash         string    `json:"hash"`
+}
+
+type Webhook struct {
+	ID           string    `json:"id"`
+	Organization string    `json:"organization"`
+	URL          string    `json:"url"`
+	Events       []string  `json:"events"`
+	Secret       string    `json:"secret"`
+	Enabled      bool      `json:"enabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	DurationMS int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type RegistryEvent struct {
+	Sequence     int64             `json:"sequence"`
+	ID           string            `json:"id"`
+	Organization string            `json:"organization"`
+	Type         string            `json:"type"`
+	Subject      string            `json:"subject"`
+	Data         map[string]string `json:"data"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type DownloadCounter struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Provider  string `json:"provider"`
+	Version   string `json:"version"`
+	Dimension string `json:"dimension"`
+	Key       string `json:"key"`
+	Count     int64  `json:"count"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
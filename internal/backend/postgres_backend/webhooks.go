@@ -0,0 +1,201 @@
+package postgres_backend
+
+import (
+	"context"
+	"fmt"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"net/http"
+	"time"
+)
+
+var _ backend.WebhooksBackend = &PostgresBackend{}
+
+func (p *PostgresBackend) WebhooksList(ctx context.Context, parameters registrytypes.APIParameters) (*models.WebhooksListResponse, error) {
+	webhooks, err := webhooksList(ctx, p.db, parameters.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhooksListResponse{
+		Data: []models.WebhooksDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(1, len(webhooks), len(webhooks)),
+		},
+	}
+
+	for _, webhook := range webhooks {
+		resp.Data = append(resp.Data, webhookData(webhook))
+	}
+
+	return resp, nil
+}
+
+func (p *PostgresBackend) WebhooksCreate(ctx context.Context, parameters registrytypes.APIParameters, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	webhook := &Webhook{
+		Organization: parameters.Organization,
+		Events:       []string{},
+		Enabled:      true,
+	}
+	applyWebhookAttributes(webhook, request.Data.Attributes)
+
+	err := webhookInsert(ctx, p.db, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (p *PostgresBackend) WebhooksGet(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (*models.WebhooksResponse, error) {
+	webhook, err := webhookSelect(ctx, p.db, parameters.Organization, webhookID)
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (p *PostgresBackend) WebhooksUpdate(ctx context.Context, parameters registrytypes.APIParameters, webhookID string, request models.WebhooksRequest) (*models.WebhooksResponse, error) {
+	webhook, err := webhookSelect(ctx, p.db, parameters.Organization, webhookID)
+	if err != nil || webhook == nil {
+		return nil, err
+	}
+
+	applyWebhookAttributes(webhook, request.Data.Attributes)
+
+	err = webhookUpdate(ctx, p.db, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhooksResponse{
+		Data: webhookData(*webhook),
+	}, nil
+}
+
+func (p *PostgresBackend) WebhooksDelete(ctx context.Context, parameters registrytypes.APIParameters, webhookID string) (int, error) {
+	deleted, err := webhookDelete(ctx, p.db, parameters.Organization, webhookID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if deleted == 0 {
+		return http.StatusNotFound, fmt.Errorf("webhook not found")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+func (p *PostgresBackend) WebhooksSubscribed(ctx context.Context, organization string) ([]registrytypes.Webhook, error) {
+	webhooks, err := webhooksList(ctx, p.db, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []registrytypes.Webhook
+	for _, webhook := range webhooks {
+		if !webhook.Enabled {
+			continue
+		}
+
+		subscribed = append(subscribed, registrytypes.Webhook{
+			ID:           webhook.ID,
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Secret:       webhook.Secret,
+			Enabled:      webhook.Enabled,
+		})
+	}
+
+	return subscribed, nil
+}
+
+func (p *PostgresBackend) WebhookDeliveriesCreate(ctx context.Context, delivery registrytypes.WebhookDelivery) error {
+	value := &WebhookDelivery{
+		WebhookID:  delivery.WebhookID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Error:      delivery.Error,
+		DurationMS: delivery.Duration.Milliseconds(),
+		Success:    delivery.Success,
+		CreatedAt:  delivery.Timestamp,
+	}
+
+	return webhookDeliveryInsert(ctx, p.db, value, backend.WebhookDeliveryRetention)
+}
+
+func (p *PostgresBackend) WebhookDeliveriesList(ctx context.Context, parameters registrytypes.WebhookDeliveryParameters) (*models.WebhookDeliveriesListResponse, error) {
+	offset := (parameters.PageNumber - 1) * parameters.PageSize
+	deliveries, pagination, err := webhookDeliveriesList(ctx, p.db, parameters.WebhookID, parameters.PageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.WebhookDeliveriesListResponse{
+		Data: []models.WebhookDeliveriesDataResponse{},
+		Meta: models.Meta{
+			Pagination: models.NewPaginationMeta(parameters.PageNumber, parameters.PageSize, pagination.TotalCount),
+		},
+	}
+
+	for _, delivery := range deliveries {
+		resp.Data = append(resp.Data, webhookDeliveryData(delivery))
+	}
+
+	return resp, nil
+}
+
+func applyWebhookAttributes(webhook *Webhook, attributes models.WebhooksAttributesRequest) {
+	if attributes.URL != nil {
+		webhook.URL = *attributes.URL
+	}
+	if attributes.Events != nil {
+		webhook.Events = *attributes.Events
+	}
+	if attributes.Secret != nil {
+		webhook.Secret = *attributes.Secret
+	}
+	if attributes.Enabled != nil {
+		webhook.Enabled = *attributes.Enabled
+	}
+}
+
+func webhookData(webhook Webhook) models.WebhooksDataResponse {
+	return models.WebhooksDataResponse{
+		ID:   webhook.ID,
+		Type: "webhooks",
+		Attributes: models.WebhooksAttributesResponse{
+			Organization: webhook.Organization,
+			URL:          webhook.URL,
+			Events:       webhook.Events,
+			Enabled:      webhook.Enabled,
+			CreatedAt:    webhook.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    webhook.UpdatedAt.Format(time.RFC3339),
+		},
+	}
+}
+
+func webhookDeliveryData(delivery WebhookDelivery) models.WebhookDeliveriesDataResponse {
+	return models.WebhookDeliveriesDataResponse{
+		ID:   delivery.ID,
+		Type: "webhook-deliveries",
+		Attributes: models.WebhookDeliveriesAttributesResponse{
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			DurationMS: delivery.DurationMS,
+			Success:    delivery.Success,
+			CreatedAt:  delivery.CreatedAt.Format(time.RFC3339Nano),
+		},
+	}
+}
@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Inspect the audit trail",
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/auth"
+	"go-terraform-registry/internal/config"
+	"os"
+	"strings"
+)
+
+type ConfigOptions struct {
+	File string
+}
+
+var configOptions = &ConfigOptions{}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Server configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the server configuration file and environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		validateConfig()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configValidateCmd.Flags().StringVar(&configOptions.File, "file", os.Getenv(config.FileEnv), "Configuration file (YAML or JSON)")
+}
+
+func validateConfig() {
+	c, err := config.LoadRegistryConfig(configOptions.File)
+	if err == nil {
+		_, err = auth.LoadTokenKeys(c)
+	}
+	if err != nil {
+		fmt.Println("Invalid configuration:")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Printf("  - %s\n", line)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("Configuration is valid (backend: %s, storage: %s)\n", c.Backend, c.StorageBackend)
+}
@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage registry organizations",
+}
+
+func init() {
+	rootCmd.AddCommand(orgCmd)
+}
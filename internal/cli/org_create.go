@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/client/api_client"
+	"net/http"
+)
+
+type OrgCreateOptions struct {
+	Endpoint        string
+	Name            string
+	Email           string
+	DefaultRegistry string
+}
+
+var orgCreateOptions = &OrgCreateOptions{}
+
+var orgCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an organization",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		orgCreate(cmd.Context())
+	},
+}
+
+func init() {
+	orgCmd.AddCommand(orgCreateCmd)
+
+	orgCreateCmd.Flags().StringVar(&orgCreateOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	orgCreateCmd.Flags().StringVar(&orgCreateOptions.Name, "name", "", "Organization name")
+	orgCreateCmd.Flags().StringVar(&orgCreateOptions.Email, "email", "", "Organization contact email")
+	orgCreateCmd.Flags().StringVar(&orgCreateOptions.DefaultRegistry, "default-registry", "private", "Registry used when none is given (private or public)")
+	orgCreateCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = orgCreateCmd.MarkFlagRequired("endpoint")
+	_ = orgCreateCmd.MarkFlagRequired("name")
+}
+
+func orgCreate(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	orgRequest := models.OrganizationsRequest{
+		Data: models.OrganizationsDataRequest{
+			Type: "organizations",
+			Attributes: models.OrganizationsAttributesRequest{
+				Name:            &orgCreateOptions.Name,
+				Email:           &orgCreateOptions.Email,
+				DefaultRegistry: &orgCreateOptions.DefaultRegistry,
+			},
+		},
+	}
+
+	org, statusCode, err := CreateOrgRequest(client, orgCreateOptions.Endpoint, orgRequest)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error creating organization [%d]: %w", statusCode, err))
+		return
+	}
+
+	if statusCode == http.StatusCreated {
+		fmt.Println(fmt.Sprintf("Organization %s created", org.Data.Attributes.Name))
+	}
+}
+
+func CreateOrgRequest(client *api_client.APIClient, endpoint string, request models.OrganizationsRequest) (*models.OrganizationsResponse, int, error) {
+	url := fmt.Sprintf("%s%s", endpoint, "/api/v2/organizations")
+
+	var response models.OrganizationsResponse
+	statusCode, err := client.PostRequest(url, request, &response)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &response, statusCode, nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/client/api_client"
+	"net/http"
+)
+
+type OrgDeleteOptions struct {
+	Endpoint     string
+	Organization string
+}
+
+var orgDeleteOptions = &OrgDeleteOptions{}
+
+var orgDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an organization with all its providers, modules, namespaces and shares",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		orgDelete(cmd.Context())
+	},
+}
+
+func init() {
+	orgCmd.AddCommand(orgDeleteCmd)
+
+	orgDeleteCmd.Flags().StringVar(&orgDeleteOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	orgDeleteCmd.Flags().StringVar(&orgDeleteOptions.Organization, "organization", "", "Registry organization")
+	orgDeleteCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = orgDeleteCmd.MarkFlagRequired("endpoint")
+	_ = orgDeleteCmd.MarkFlagRequired("organization")
+}
+
+func orgDelete(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	statusCode, err := DeleteOrgRequest(client, orgDeleteOptions.Endpoint)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error deleting organization [%d]: %w", statusCode, err))
+		return
+	}
+
+	if statusCode == http.StatusNoContent {
+		fmt.Println(fmt.Sprintf("Organization %s deleted", orgDeleteOptions.Organization))
+	}
+}
+
+func DeleteOrgRequest(client *api_client.APIClient, endpoint string) (int, error) {
+	apiEndpoint := fmt.Sprintf("/api/v2/organizations/%s", orgDeleteOptions.Organization)
+	url := fmt.Sprintf("%s%s", endpoint, apiEndpoint)
+
+	return client.DeleteRequest(url)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/client/api_client"
+)
+
+type OrgListOptions struct {
+	Endpoint string
+}
+
+var orgListOptions = &OrgListOptions{}
+
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List organizations available to the token",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		orgList(cmd.Context())
+	},
+}
+
+func init() {
+	orgCmd.AddCommand(orgListCmd)
+
+	orgListCmd.Flags().StringVar(&orgListOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	orgListCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = orgListCmd.MarkFlagRequired("endpoint")
+}
+
+func orgList(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	orgs, _, err := ListOrgsRequest(client, orgListOptions.Endpoint)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Organizations:")
+	for _, org := range orgs.Data {
+		fmt.Println(fmt.Sprintf("%s %s %s", org.Attributes.Name, org.Attributes.DefaultRegistry, org.Attributes.Email))
+	}
+}
+
+func ListOrgsRequest(client *api_client.APIClient, endpoint string) (*models.OrganizationsListResponse, int, error) {
+	url := fmt.Sprintf("%s%s", endpoint, "/api/v2/organizations")
+
+	var response models.OrganizationsListResponse
+	statusCode, err := client.GetRequest(url, &response)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &response, statusCode, nil
+}
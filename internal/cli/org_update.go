@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/client/api_client"
+	"net/http"
+)
+
+type OrgUpdateOptions struct {
+	Endpoint        string
+	Organization    string
+	Name            string
+	Email           string
+	DefaultRegistry string
+}
+
+var orgUpdateOptions = &OrgUpdateOptions{}
+
+var orgUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Rename an organization or change its metadata",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		orgUpdate(cmd)
+	},
+}
+
+func init() {
+	orgCmd.AddCommand(orgUpdateCmd)
+
+	orgUpdateCmd.Flags().StringVar(&orgUpdateOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	orgUpdateCmd.Flags().StringVar(&orgUpdateOptions.Organization, "organization", "", "Registry organization")
+	orgUpdateCmd.Flags().StringVar(&orgUpdateOptions.Name, "name", "", "New organization name")
+	orgUpdateCmd.Flags().StringVar(&orgUpdateOptions.Email, "email", "", "Organization contact email")
+	orgUpdateCmd.Flags().StringVar(&orgUpdateOptions.DefaultRegistry, "default-registry", "", "Registry used when none is given (private or public)")
+	orgUpdateCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = orgUpdateCmd.MarkFlagRequired("endpoint")
+	_ = orgUpdateCmd.MarkFlagRequired("organization")
+}
+
+func orgUpdate(cmd *cobra.Command) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	// Only send the attributes that were given so the rest stay unchanged
+	attributes := models.OrganizationsAttributesRequest{}
+	if cmd.Flags().Changed("name") {
+		attributes.Name = &orgUpdateOptions.Name
+	}
+	if cmd.Flags().Changed("email") {
+		attributes.Email = &orgUpdateOptions.Email
+	}
+	if cmd.Flags().Changed("default-registry") {
+		attributes.DefaultRegistry = &orgUpdateOptions.DefaultRegistry
+	}
+
+	orgRequest := models.OrganizationsRequest{
+		Data: models.OrganizationsDataRequest{
+			Type:       "organizations",
+			Attributes: attributes,
+		},
+	}
+
+	org, statusCode, err := UpdateOrgRequest(client, orgUpdateOptions.Endpoint, orgRequest)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error updating organization [%d]: %w", statusCode, err))
+		return
+	}
+
+	if statusCode == http.StatusOK {
+		fmt.Println(fmt.Sprintf("Organization %s updated", org.Data.Attributes.Name))
+	}
+}
+
+func UpdateOrgRequest(client *api_client.APIClient, endpoint string, request models.OrganizationsRequest) (*models.OrganizationsResponse, int, error) {
+	apiEndpoint := fmt.Sprintf("/api/v2/organizations/%s", orgUpdateOptions.Organization)
+	url := fmt.Sprintf("%s%s", endpoint, apiEndpoint)
+
+	var response models.OrganizationsResponse
+	statusCode, err := client.PatchRequest(url, request, &response)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &response, statusCode, nil
+}
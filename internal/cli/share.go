@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Manage provider and module shares between organizations",
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/client/api_client"
+	"net/http"
+)
+
+type ShareCreateOptions struct {
+	Endpoint     string
+	Organization string
+	Grantee      string
+	Name         string
+	Provider     string
+}
+
+var shareCreateOptions = &ShareCreateOptions{}
+
+var shareCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Grant another organization read access to a provider or module",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		shareCreate(cmd.Context())
+	},
+}
+
+func init() {
+	shareCmd.AddCommand(shareCreateCmd)
+
+	shareCreateCmd.Flags().StringVar(&shareCreateOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	shareCreateCmd.Flags().StringVar(&shareCreateOptions.Organization, "organization", "", "Registry organization")
+	shareCreateCmd.Flags().StringVar(&shareCreateOptions.Grantee, "grantee", "", "Organization receiving access")
+	shareCreateCmd.Flags().StringVar(&shareCreateOptions.Name, "name", "", "Provider or module name")
+	shareCreateCmd.Flags().StringVar(&shareCreateOptions.Provider, "provider", "", "Module provider, shares a module instead of a provider")
+	shareCreateCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = shareCreateCmd.MarkFlagRequired("endpoint")
+	_ = shareCreateCmd.MarkFlagRequired("organization")
+	_ = shareCreateCmd.MarkFlagRequired("grantee")
+	_ = shareCreateCmd.MarkFlagRequired("name")
+}
+
+func shareCreate(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	resourceType := "registry-providers"
+	if shareCreateOptions.Provider != "" {
+		resourceType = "registry-modules"
+	}
+
+	shareRequest := models.SharesRequest{
+		Data: models.SharesDataRequest{
+			Type: "registry-shares",
+			Attributes: models.SharesAttributesRequest{
+				GranteeOrganization: shareCreateOptions.Grantee,
+				ResourceType:        resourceType,
+				Namespace:           shareCreateOptions.Organization,
+				Name:                shareCreateOptions.Name,
+				Provider:            shareCreateOptions.Provider,
+			},
+		},
+	}
+
+	share, statusCode, err := CreateShareRequest(client, shareCreateOptions.Endpoint, shareRequest)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error creating share [%d]: %w", statusCode, err))
+		return
+	}
+
+	if statusCode == http.StatusCreated {
+		fmt.Println(fmt.Sprintf("Share %s created, %s can read %s", share.Data.ID, share.Data.Attributes.GranteeOrganization, share.Data.Attributes.Name))
+	}
+}
+
+func CreateShareRequest(client *api_client.APIClient, endpoint string, request models.SharesRequest) (*models.SharesResponse, int, error) {
+	apiEndpoint := fmt.Sprintf("/api/v2/organizations/%s/registry-shares", shareCreateOptions.Organization)
+	url := fmt.Sprintf("%s%s", endpoint, apiEndpoint)
+
+	var response models.SharesResponse
+	statusCode, err := client.PostRequest(url, request, &response)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &response, statusCode, nil
+}
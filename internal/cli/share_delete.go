@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/client/api_client"
+	"net/http"
+)
+
+type ShareDeleteOptions struct {
+	Endpoint     string
+	Organization string
+	ID           string
+}
+
+var shareDeleteOptions = &ShareDeleteOptions{}
+
+var shareDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Revoke a share",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		shareDelete(cmd.Context())
+	},
+}
+
+func init() {
+	shareCmd.AddCommand(shareDeleteCmd)
+
+	shareDeleteCmd.Flags().StringVar(&shareDeleteOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	shareDeleteCmd.Flags().StringVar(&shareDeleteOptions.Organization, "organization", "", "Registry organization")
+	shareDeleteCmd.Flags().StringVar(&shareDeleteOptions.ID, "id", "", "Share id")
+	shareDeleteCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = shareDeleteCmd.MarkFlagRequired("endpoint")
+	_ = shareDeleteCmd.MarkFlagRequired("organization")
+	_ = shareDeleteCmd.MarkFlagRequired("id")
+}
+
+func shareDelete(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	statusCode, err := DeleteShareRequest(client, shareDeleteOptions.Endpoint)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error deleting share [%d]: %w", statusCode, err))
+		return
+	}
+
+	if statusCode == http.StatusNoContent {
+		fmt.Println(fmt.Sprintf("Share %s deleted", shareDeleteOptions.ID))
+	}
+}
+
+func DeleteShareRequest(client *api_client.APIClient, endpoint string) (int, error) {
+	apiEndpoint := fmt.Sprintf("/api/v2/organizations/%s/registry-shares/%s", shareDeleteOptions.Organization, shareDeleteOptions.ID)
+	url := fmt.Sprintf("%s%s", endpoint, apiEndpoint)
+
+	return client.DeleteRequest(url)
+}
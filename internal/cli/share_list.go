@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/client/api_client"
+)
+
+type ShareListOptions struct {
+	Endpoint     string
+	Organization string
+}
+
+var shareListOptions = &ShareListOptions{}
+
+var shareListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List shares granted by an organization",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		endpoint, _ := cmd.Flags().GetString("endpoint")
+		authToken, _ := cmd.Flags().GetString("auth-token")
+		if authToken == "" && !setAuthTokenFromEnv(endpoint) {
+			_ = authToken
+			return errors.New("required flag(s) \"auth-token\" not set")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		shareList(cmd.Context())
+	},
+}
+
+func init() {
+	shareCmd.AddCommand(shareListCmd)
+
+	shareListCmd.Flags().StringVar(&shareListOptions.Endpoint, "endpoint", "", "Registry endpoint")
+	shareListCmd.Flags().StringVar(&shareListOptions.Organization, "organization", "", "Registry organization")
+	shareListCmd.Flags().StringVar(&authenticationOptions.Token, "auth-token", "", "Authorization token")
+
+	_ = shareListCmd.MarkFlagRequired("endpoint")
+	_ = shareListCmd.MarkFlagRequired("organization")
+}
+
+func shareList(_ context.Context) {
+	client := api_client.NewAPIClient(authenticationOptions.Token)
+
+	shares, _, err := ListSharesRequest(client, shareListOptions.Endpoint)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Shares:")
+	for _, share := range shares.Data {
+		name := share.Attributes.Name
+		if share.Attributes.Provider != "" {
+			name = fmt.Sprintf("%s/%s", name, share.Attributes.Provider)
+		}
+		fmt.Println(fmt.Sprintf("%s %s %s -> %s", share.ID, share.Attributes.ResourceType, name, share.Attributes.GranteeOrganization))
+	}
+}
+
+func ListSharesRequest(client *api_client.APIClient, endpoint string) (*models.SharesListResponse, int, error) {
+	apiEndpoint := fmt.Sprintf("/api/v2/organizations/%s/registry-shares", shareListOptions.Organization)
+	url := fmt.Sprintf("%s%s", endpoint, apiEndpoint)
+
+	var response models.SharesListResponse
+	statusCode, err := client.GetRequest(url, &response)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &response, statusCode, nil
+}
@@ -9,9 +9,12 @@ import (
 )
 
 type TokenOptions struct {
-	Key          string
-	User         string
-	Organization []string
+	Key            string
+	KeyID          string
+	SigningMethod  string
+	SigningKeyFile string
+	User           string
+	Organization   []string
 }
 
 var tokenOptions = &TokenOptions{}
@@ -34,19 +37,29 @@ func init() {
 	tokenCmd.AddCommand(generateCmd)
 
 	tokenKey := os.Getenv("TOKEN_ENCRYPTION_KEY")
+	signingKeyFile := os.Getenv("TOKEN_SIGNING_KEY_FILE")
 	generateCmd.Flags().StringVar(&tokenOptions.Key, "key", tokenKey, "Token encryption key")
+	generateCmd.Flags().StringVar(&tokenOptions.KeyID, "key-id", os.Getenv("TOKEN_ENCRYPTION_KEY_ID"), "Key id of tokens signed with the encryption key, defaults to "+auth.DefaultSecretKeyID)
+	generateCmd.Flags().StringVar(&tokenOptions.SigningMethod, "signing-method", os.Getenv("TOKEN_SIGNING_METHOD"), "Token signing method [HS256, RS256, ES256, EdDSA]")
+	generateCmd.Flags().StringVar(&tokenOptions.SigningKeyFile, "signing-key-file", signingKeyFile, "PEM encoded private key used for asymmetric signing methods")
 	generateCmd.Flags().StringVar(&tokenOptions.User, "user", "", "User name")
 	generateCmd.Flags().StringSliceVar(&tokenOptions.Organization, "organization", []string{""}, "Organization")
 
 	_ = generateCmd.MarkFlagRequired("user")
 	_ = generateCmd.MarkFlagRequired("organization")
-	if tokenKey == "" {
-		_ = generateCmd.MarkFlagRequired("key")
+	if tokenKey == "" && signingKeyFile == "" {
+		generateCmd.MarkFlagsOneRequired("key", "signing-key-file")
 	}
 }
 
 func generateToken(_ context.Context) {
-	token, err := auth.CreateJWTClaimsToken(tokenOptions.User, tokenOptions.Organization, []byte(tokenOptions.Key))
+	keys, err := auth.NewTokenKeys(tokenOptions.SigningMethod, tokenOptions.SigningKeyFile, nil, []byte(tokenOptions.Key), tokenOptions.KeyID, false)
+	if err != nil {
+		fmt.Printf("Error loading signing key: %v\n", err)
+		return
+	}
+
+	token, err := auth.CreateJWTClaimsToken(tokenOptions.User, tokenOptions.Organization, keys)
 	if err != nil {
 		fmt.Printf("Error generating token: %v\n", err)
 		return
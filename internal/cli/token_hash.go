@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"go-terraform-registry/internal/auth"
+)
+
+type TokenHashOptions struct {
+	Token     string
+	Algorithm string
+}
+
+var tokenHashOptions = &TokenHashOptions{}
+
+var tokenHashCmd = &cobra.Command{
+	Use:   "hash",
+	Short: "Hash a token for the static token file",
+	Run: func(cmd *cobra.Command, args []string) {
+		hashToken(cmd.Context())
+	},
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenHashCmd)
+
+	tokenHashCmd.Flags().StringVar(&tokenHashOptions.Token, "token", "", "Token to hash (tfr_<id>_<secret>), a random token is generated when empty")
+	tokenHashCmd.Flags().StringVar(&tokenHashOptions.Algorithm, "algorithm", "argon2id", "Hash algorithm [argon2id, bcrypt]")
+}
+
+func hashToken(_ context.Context) {
+	token := tokenHashOptions.Token
+	if token == "" {
+		generated, _, err := auth.GenerateStaticToken()
+		if err != nil {
+			fmt.Printf("Error generating token: %v\n", err)
+			return
+		}
+		token = generated
+		fmt.Printf("Generated token: %s\n", token)
+	}
+
+	id, ok := auth.ParseStaticToken(token)
+	if !ok {
+		fmt.Println("Error hashing token: static tokens have the form tfr_<id>_<secret>")
+		return
+	}
+
+	hash, err := auth.HashStaticToken(token, tokenHashOptions.Algorithm)
+	if err != nil {
+		fmt.Printf("Error hashing token: %v\n", err)
+		return
+	}
+
+	fmt.Printf("ID: %s\n", id)
+	fmt.Printf("Hash: %s\n", hash)
+}
@@ -27,6 +27,14 @@ type ErrorMessage struct {
 }
 
 func (c *APIClient) PostRequest(url string, requestBody any, result any) (int, error) {
+	return c.bodyRequest("POST", url, requestBody, result)
+}
+
+func (c *APIClient) PatchRequest(url string, requestBody any, result any) (int, error) {
+	return c.bodyRequest("PATCH", url, requestBody, result)
+}
+
+func (c *APIClient) bodyRequest(method string, url string, requestBody any, result any) (int, error) {
 	var bodyReader io.Reader
 	if requestBody != nil {
 		jsonData, err := json.Marshal(requestBody)
@@ -36,7 +44,7 @@ func (c *APIClient) PostRequest(url string, requestBody any, result any) (int, e
 		bodyReader = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest("POST", url, bodyReader)
+	req, err := http.NewRequest(method, url, bodyReader)
 	if err != nil {
 		return -1, fmt.Errorf("error creating request: %w", err)
 	}
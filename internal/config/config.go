@@ -1,52 +1,381 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// FileEnv names the configuration file, settings from the environment take
+// precedence over the file.
+const FileEnv = "CONFIG_FILE"
+
 type RegistryConfig struct {
-	AllowAnonymousAccess   bool
-	AssumeRoleARN          string
-	Backend                string
-	GitHubEndpoint         string
-	OauthClientID          string
-	OauthClientRedirectURL string
-	OauthClientSecret      string
-	Organization           string
-	S3BucketName           string
-	S3BucketRegion         string
-	StorageBackend         string
-	TokenEncryptionKey     string
-}
-
-func GetRegistryConfig() RegistryConfig {
-	config := RegistryConfig{
-		AllowAnonymousAccess:   getBoolEnv("ALLOW_ANONYMOUS_ACCESS", true),
-		AssumeRoleARN:          os.Getenv("ASSUME_ROLE_ARN"),
-		Backend:                os.Getenv("BACKEND"),
-		GitHubEndpoint:         os.Getenv("GITHUB_ENDPOINT"),
-		OauthClientID:          os.Getenv("OAUTH_CLIENT_ID"),
-		OauthClientRedirectURL: os.Getenv("OAUTH_CLIENT_REDIRECT_URL"),
-		OauthClientSecret:      os.Getenv("OAUTH_CLIENT_SECRET"),
-		Organization:           os.Getenv("DEFAULT_ORGANIZATION"),
-		S3BucketName:           os.Getenv("S3_BUCKET_NAME"),
-		S3BucketRegion:         os.Getenv("S3_BUCKET_REGION"),
-		StorageBackend:         os.Getenv("STORAGE_BACKEND"),
-		TokenEncryptionKey:     os.Getenv("TOKEN_ENCRYPTION_KEY"),
-	}
-	if config.Organization == "" {
-		config.Organization = "default"
-	}
-	return config
-}
-
-func getBoolEnv(key string, defaultValue bool) bool {
-	val, exists := os.LookupEnv(key)
-	if !exists {
-		return defaultValue
-	}
-
-	val = strings.ToLower(val)
-	return val == "true" || val == "1"
+	// AdminAddress serves metrics, health details and the maintenance toggle.
+	// It only listens on loopback by default, bind it to another interface
+	// only where the port is not reachable by registry clients.
+	AdminAddress string `yaml:"admin_address"`
+	// AdminToken is the bearer token changing settings on the admin endpoints
+	// requires, without one only loopback clients may change them.
+	AdminToken           string `yaml:"admin_token"`
+	AllowAnonymousAccess bool   `yaml:"allow_anonymous_access"`
+	// AuditSigningKey signs the checkpoint closing an audit trail export.
+	// Without one checkpoints are unsigned and only show truncation of the
+	// export after it was taken.
+	AuditSigningKey        string             `yaml:"audit_signing_key"`
+	Backend                string             `yaml:"backend"`
+	BadgerDB               BadgerDBConfig     `yaml:"badgerdb"`
+	CacheControl           CacheControlConfig `yaml:"cache_control"`
+	DynamoDB               DynamoDBConfig     `yaml:"dynamodb"`
+	GitHubEndpoint         string             `yaml:"github_endpoint"`
+	IdleTimeout            time.Duration      `yaml:"idle_timeout"`
+	ListenAddress          string             `yaml:"listen_address"`
+	LocalStorage           LocalStorageConfig `yaml:"local_storage"`
+	LogFormat              string             `yaml:"log_format"`
+	LogLevel               string             `yaml:"log_level"`
+	Maintenance            MaintenanceConfig  `yaml:"maintenance"`
+	OIDCConfigFile         string             `yaml:"oidc_config_file"`
+	OauthClientID          string             `yaml:"oauth_client_id"`
+	OauthClientRedirectURL string             `yaml:"oauth_client_redirect_url"`
+	OauthClientSecret      string             `yaml:"oauth_client_secret"`
+	Organization           string             `yaml:"default_organization"`
+	Postgres               PostgresConfig     `yaml:"postgres"`
+	RateLimit              RateLimitConfig    `yaml:"rate_limit"`
+	ReadCache              ReadCacheConfig    `yaml:"read_cache"`
+	ReadHeaderTimeout      time.Duration      `yaml:"read_header_timeout"`
+	ReadTimeout            time.Duration      `yaml:"read_timeout"`
+	S3                     S3Config           `yaml:"s3"`
+	ShutdownTimeout        time.Duration      `yaml:"shutdown_timeout"`
+	StaticTokenFile        string             `yaml:"static_token_file"`
+	StorageBackend         string             `yaml:"storage_backend"`
+	TLSCertFile            string             `yaml:"tls_cert_file"`
+	TLSClientAuth          string             `yaml:"tls_client_auth"`
+	TLSClientCAFile        string             `yaml:"tls_client_ca_file"`
+	TLSClientIdentityFile  string             `yaml:"tls_client_identity_file"`
+	TLSKeyFile             string             `yaml:"tls_key_file"`
+	// TokenAcceptHMAC keeps accepting tokens signed with the encryption key
+	// after switching to an asymmetric signing method, until they expire.
+	TokenAcceptHMAC    bool   `yaml:"token_accept_hmac"`
+	TokenEncryptionKey string `yaml:"token_encryption_key"`
+	// TokenEncryptionKeyID is the kid of tokens signed with the encryption
+	// key, "hmac" unless set.
+	TokenEncryptionKeyID      string        `yaml:"token_encryption_key_id"`
+	TokenSigningKeyFile       string        `yaml:"token_signing_key_file"`
+	TokenSigningMethod        string        `yaml:"token_signing_method"`
+	TokenVerificationKeyFiles []string      `yaml:"token_verification_key_files"`
+	TracingEndpoint           string        `yaml:"tracing_endpoint"`
+	TracingSampleRatio        float64       `yaml:"tracing_sample_ratio"`
+	WebhookMaxAttempts        int           `yaml:"webhook_max_attempts"`
+	WebhookTimeout            time.Duration `yaml:"webhook_timeout"`
+	WebhookWorkers            int           `yaml:"webhook_workers"`
+	WriteTimeout              time.Duration `yaml:"write_timeout"`
+}
+
+type BadgerDBConfig struct {
+	Path string `yaml:"path"`
+}
+
+// CacheControlConfig holds the Cache-Control headers of the registry protocol
+// responses. Discovery and versions carry an ETag to revalidate them with,
+// download metadata holds signed URLs and must not be cached for longer than
+// they are valid. Shared caches only store responses to authenticated
+// requests when marked public, they then serve them without the namespace
+// access checks.
+type CacheControlConfig struct {
+	Discovery string `yaml:"discovery"`
+	Downloads string `yaml:"downloads"`
+	Versions  string `yaml:"versions"`
+}
+
+type DynamoDBConfig struct {
+	AssumeRoleARN string `yaml:"assume_role_arn"`
+}
+
+// MaintenanceConfig puts the registry in read-only mode, changes are refused
+// with Message while the protocol endpoints and downloads keep serving.
+type MaintenanceConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Message string `yaml:"message"`
+}
+
+type PostgresConfig struct {
+	DatabaseURL string `yaml:"database_url"`
+}
+
+type LocalStorageConfig struct {
+	AssetsEndpoint string `yaml:"assets_endpoint"`
+	AssetsPath     string `yaml:"assets_path"`
+}
+
+// RateLimitConfig holds the budgets of the clients, keyed by login or client
+// IP. A budget without a rate is unlimited. With TrustForwardedFor the client
+// IP is the X-Forwarded-For entry added by the outermost of TrustedProxies
+// proxies in front of the registry, entries left of it are client supplied.
+type RateLimitConfig struct {
+	Reads             RateLimitBudget `yaml:"reads"`
+	Store             string          `yaml:"store"`
+	TrustForwardedFor bool            `yaml:"trust_forwarded_for"`
+	TrustedProxies    int             `yaml:"trusted_proxies"`
+	Uploads           RateLimitBudget `yaml:"uploads"`
+	Writes            RateLimitBudget `yaml:"writes"`
+}
+
+// RateLimitBudget allows Burst requests at once, refilled at Rate requests per
+// second. Burst defaults to a second worth of requests.
+type RateLimitBudget struct {
+	Burst int     `yaml:"burst"`
+	Rate  float64 `yaml:"rate"`
+}
+
+// ReadCacheConfig caches the provider and module lookups of the registry
+// protocol for TTL, keeping at most MaxEntries. It is disabled without a TTL.
+type ReadCacheConfig struct {
+	MaxEntries int           `yaml:"max_entries"`
+	TTL        time.Duration `yaml:"ttl"`
+}
+
+type S3Config struct {
+	AssumeRoleARN string `yaml:"assume_role_arn"`
+	BucketName    string `yaml:"bucket_name"`
+	BucketRegion  string `yaml:"bucket_region"`
+}
+
+func defaultRegistryConfig() RegistryConfig {
+	return RegistryConfig{
+		AdminAddress:         "127.0.0.1:9090",
+		AllowAnonymousAccess: true,
+		Backend:              "badgerdb",
+		BadgerDB: BadgerDBConfig{
+			Path: "registry_db",
+		},
+		CacheControl: CacheControlConfig{
+			Discovery: "public, max-age=3600",
+			Downloads: "no-cache",
+			Versions:  "no-cache",
+		},
+		IdleTimeout:   2 * time.Minute,
+		ListenAddress: ":8080",
+		LocalStorage: LocalStorageConfig{
+			AssetsEndpoint: "http://localhost:8080",
+		},
+		Organization: "default",
+		RateLimit: RateLimitConfig{
+			Store:          "memory",
+			TrustedProxies: 1,
+		},
+		ReadCache: ReadCacheConfig{
+			MaxEntries: 10000,
+		},
+		ReadHeaderTimeout:  10 * time.Second,
+		ReadTimeout:        10 * time.Minute,
+		ShutdownTimeout:    30 * time.Second,
+		StorageBackend:     "local",
+		TracingSampleRatio: 1,
+		WebhookMaxAttempts: 5,
+		WebhookTimeout:     10 * time.Second,
+		WebhookWorkers:     4,
+		WriteTimeout:       10 * time.Minute,
+	}
+}
+
+// LoadRegistryConfig reads the configuration file at path, if any, applies
+// the environment on top of it and validates the result.
+func LoadRegistryConfig(path string) (RegistryConfig, error) {
+	config := defaultRegistryConfig()
+
+	if path != "" {
+		if err := readFile(path, &config); err != nil {
+			return config, err
+		}
+	}
+
+	if err := applyEnv(&config); err != nil {
+		return config, err
+	}
+
+	return config, config.Validate()
+}
+
+// readFile decodes a YAML or JSON file into config, keys not known to the
+// configuration are rejected so typos do not go unnoticed.
+func readFile(path string, config *RegistryConfig) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error reading configuration file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error parsing configuration file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func applyEnv(config *RegistryConfig) error {
+	e := &env{}
+
+	e.string(&config.AdminAddress, "ADMIN_ADDRESS")
+	e.string(&config.AdminToken, "ADMIN_TOKEN")
+	e.bool(&config.AllowAnonymousAccess, "ALLOW_ANONYMOUS_ACCESS")
+	e.string(&config.AuditSigningKey, "AUDIT_SIGNING_KEY")
+	e.string(&config.DynamoDB.AssumeRoleARN, "ASSUME_ROLE_ARN")
+	e.string(&config.S3.AssumeRoleARN, "ASSUME_ROLE_ARN")
+	e.string(&config.Backend, "BACKEND")
+	e.string(&config.BadgerDB.Path, "BADGER_DB_PATH")
+	e.string(&config.CacheControl.Discovery, "CACHE_CONTROL_DISCOVERY")
+	e.string(&config.CacheControl.Downloads, "CACHE_CONTROL_DOWNLOADS")
+	e.string(&config.CacheControl.Versions, "CACHE_CONTROL_VERSIONS")
+	e.string(&config.Postgres.DatabaseURL, "DATABASE_URL")
+	e.string(&config.Organization, "DEFAULT_ORGANIZATION")
+	e.string(&config.GitHubEndpoint, "GITHUB_ENDPOINT")
+	e.duration(&config.IdleTimeout, "IDLE_TIMEOUT")
+	e.string(&config.ListenAddress, "LISTEN_ADDRESS")
+	e.string(&config.LocalStorage.AssetsEndpoint, "LOCAL_STORAGE_ASSETS_ENDPOINT")
+	e.string(&config.LocalStorage.AssetsPath, "LOCAL_STORAGE_ASSETS_PATH")
+	e.string(&config.LogFormat, "LOG_FORMAT")
+	e.string(&config.LogLevel, "LOG_LEVEL")
+	e.bool(&config.Maintenance.Enabled, "MAINTENANCE_MODE")
+	e.string(&config.Maintenance.Message, "MAINTENANCE_MESSAGE")
+	e.string(&config.OauthClientID, "OAUTH_CLIENT_ID")
+	e.string(&config.OauthClientRedirectURL, "OAUTH_CLIENT_REDIRECT_URL")
+	e.string(&config.OauthClientSecret, "OAUTH_CLIENT_SECRET")
+	e.string(&config.OIDCConfigFile, "OIDC_CONFIG_FILE")
+	e.int(&config.RateLimit.Reads.Burst, "RATE_LIMIT_READS_BURST")
+	e.float(&config.RateLimit.Reads.Rate, "RATE_LIMIT_READS_RATE")
+	e.string(&config.RateLimit.Store, "RATE_LIMIT_STORE")
+	e.bool(&config.RateLimit.TrustForwardedFor, "RATE_LIMIT_TRUST_FORWARDED_FOR")
+	e.int(&config.RateLimit.TrustedProxies, "RATE_LIMIT_TRUSTED_PROXIES")
+	e.int(&config.RateLimit.Uploads.Burst, "RATE_LIMIT_UPLOADS_BURST")
+	e.float(&config.RateLimit.Uploads.Rate, "RATE_LIMIT_UPLOADS_RATE")
+	e.int(&config.RateLimit.Writes.Burst, "RATE_LIMIT_WRITES_BURST")
+	e.float(&config.RateLimit.Writes.Rate, "RATE_LIMIT_WRITES_RATE")
+	e.int(&config.ReadCache.MaxEntries, "READ_CACHE_MAX_ENTRIES")
+	e.duration(&config.ReadCache.TTL, "READ_CACHE_TTL")
+	e.duration(&config.ReadHeaderTimeout, "READ_HEADER_TIMEOUT")
+	e.duration(&config.ReadTimeout, "READ_TIMEOUT")
+	e.string(&config.S3.BucketName, "S3_BUCKET_NAME")
+	e.string(&config.S3.BucketRegion, "S3_BUCKET_REGION")
+	e.duration(&config.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	e.string(&config.StaticTokenFile, "STATIC_TOKEN_FILE")
+	e.string(&config.StorageBackend, "STORAGE_BACKEND")
+	e.string(&config.TLSCertFile, "TLS_CERT_FILE")
+	e.string(&config.TLSClientAuth, "TLS_CLIENT_AUTH")
+	e.string(&config.TLSClientCAFile, "TLS_CLIENT_CA_FILE")
+	e.string(&config.TLSClientIdentityFile, "TLS_CLIENT_IDENTITY_FILE")
+	e.string(&config.TLSKeyFile, "TLS_KEY_FILE")
+	e.bool(&config.TokenAcceptHMAC, "TOKEN_ACCEPT_HMAC")
+	e.string(&config.TokenEncryptionKey, "TOKEN_ENCRYPTION_KEY")
+	e.string(&config.TokenEncryptionKeyID, "TOKEN_ENCRYPTION_KEY_ID")
+	e.string(&config.TokenSigningKeyFile, "TOKEN_SIGNING_KEY_FILE")
+	e.string(&config.TokenSigningMethod, "TOKEN_SIGNING_METHOD")
+	e.list(&config.TokenVerificationKeyFiles, "TOKEN_VERIFICATION_KEY_FILES")
+	e.string(&config.TracingEndpoint, "TRACING_ENDPOINT")
+	e.float(&config.TracingSampleRatio, "TRACING_SAMPLE_RATIO")
+	e.int(&config.WebhookMaxAttempts, "WEBHOOK_MAX_ATTEMPTS")
+	e.duration(&config.WebhookTimeout, "WEBHOOK_TIMEOUT")
+	e.int(&config.WebhookWorkers, "WEBHOOK_WORKERS")
+	e.duration(&config.WriteTimeout, "WRITE_TIMEOUT")
+
+	return errors.Join(e.errs...)
+}
+
+// env overrides configuration values with the environment variables that are
+// set and not empty, collecting the values that cannot be parsed.
+type env struct {
+	errs []error
+}
+
+func (e *env) lookup(key string) (string, bool) {
+	val := os.Getenv(key)
+	return val, val != ""
+}
+
+func (e *env) invalid(key string, val string, err error) {
+	e.errs = append(e.errs, fmt.Errorf("invalid %s %q: %w", key, val, err))
+}
+
+func (e *env) string(target *string, key string) {
+	if val, ok := e.lookup(key); ok {
+		*target = val
+	}
+}
+
+func (e *env) bool(target *bool, key string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		e.invalid(key, val, err)
+		return
+	}
+	*target = b
+}
+
+func (e *env) int(target *int, key string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		e.invalid(key, val, err)
+		return
+	}
+	*target = i
+}
+
+func (e *env) float(target *float64, key string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		e.invalid(key, val, err)
+		return
+	}
+	*target = f
+}
+
+func (e *env) duration(target *time.Duration, key string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		e.invalid(key, val, err)
+		return
+	}
+	*target = d
+}
+
+func (e *env) list(target *[]string, key string) {
+	val, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	var values []string
+	for _, v := range strings.Split(val, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	*target = values
 }
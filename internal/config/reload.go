@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// reloadable are the fields that can change without a restart. Anything else
+// is bound to listeners, connections or workers set up at startup.
+var reloadable = []string{
+	"AllowAnonymousAccess",
+	"LogLevel",
+	"Maintenance",
+	"OIDCConfigFile",
+	"OauthClientID",
+	"OauthClientRedirectURL",
+	"OauthClientSecret",
+	"StaticTokenFile",
+	"TokenAcceptHMAC",
+	"TokenEncryptionKey",
+	"TokenEncryptionKeyID",
+	"TokenSigningKeyFile",
+	"TokenSigningMethod",
+	"TokenVerificationKeyFiles",
+	"WebhookMaxAttempts",
+	"WebhookTimeout",
+}
+
+// ReloadFunc prepares the state derived from a reloaded configuration. The
+// returned function installs it, it is only called once every ReloadFunc has
+// succeeded.
+type ReloadFunc func(RegistryConfig) (func(), error)
+
+// Reloader holds the configuration in effect. Reload reads the file and the
+// environment again, so a setting made in the environment keeps overriding
+// the file.
+type Reloader struct {
+	path    string
+	current atomic.Pointer[RegistryConfig]
+
+	mu    sync.Mutex
+	hooks []ReloadFunc
+}
+
+func NewReloader(path string, config RegistryConfig) *Reloader {
+	r := &Reloader{
+		path: path,
+	}
+	r.current.Store(&config)
+
+	return r
+}
+
+// Current returns the configuration in effect.
+func (r *Reloader) Current() RegistryConfig {
+	return *r.current.Load()
+}
+
+// OnReload registers f to be called on every reload.
+func (r *Reloader) OnReload(f ReloadFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, f)
+}
+
+// Reload loads and validates the configuration and swaps it in. It is
+// refused as a whole when a setting that needs a restart has changed or any
+// derived state cannot be prepared, the previous configuration stays in
+// effect then.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config, err := LoadRegistryConfig(r.path)
+	if err != nil {
+		return err
+	}
+
+	current := r.Current()
+	changed := changedFields(current, config)
+
+	var restart []string
+	for _, field := range changed {
+		if !slices.Contains(reloadable, field.Name) {
+			restart = append(restart, fieldKey(field))
+		}
+	}
+	if len(restart) > 0 {
+		return fmt.Errorf("changes to %s require a restart", strings.Join(restart, ", "))
+	}
+
+	commits := make([]func(), 0, len(r.hooks))
+	for _, hook := range r.hooks {
+		commit, err := hook(config)
+		if err != nil {
+			return err
+		}
+		commits = append(commits, commit)
+	}
+
+	for _, commit := range commits {
+		commit()
+	}
+	r.current.Store(&config)
+
+	keys := make([]string, 0, len(changed))
+	for _, field := range changed {
+		keys = append(keys, fieldKey(field))
+	}
+	slog.Info("Reloaded configuration", "changed", keys)
+
+	return nil
+}
+
+func changedFields(a RegistryConfig, b RegistryConfig) []reflect.StructField {
+	av := reflect.ValueOf(a)
+	bv := reflect.ValueOf(b)
+
+	var changed []reflect.StructField
+	for i := range av.NumField() {
+		if !reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			changed = append(changed, av.Type().Field(i))
+		}
+	}
+
+	return changed
+}
+
+func fieldKey(field reflect.StructField) string {
+	if key := field.Tag.Get("yaml"); key != "" {
+		return key
+	}
+	return field.Name
+}
+
+// Live is a value derived from the configuration, rebuilt on every reload.
+type Live[T any] struct {
+	value atomic.Pointer[T]
+}
+
+// NewLive builds the value from the configuration in effect and rebuilds it
+// whenever r reloads.
+func NewLive[T any](r *Reloader, build func(RegistryConfig) (T, error)) (*Live[T], error) {
+	value, err := build(r.Current())
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Live[T]{}
+	l.value.Store(&value)
+
+	r.OnReload(func(c RegistryConfig) (func(), error) {
+		value, err := build(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			l.value.Store(&value)
+		}, nil
+	})
+
+	return l, nil
+}
+
+// Get returns the value built from the configuration in effect.
+func (l *Live[T]) Get() T {
+	return *l.value.Load()
+}
@@ -2,15 +2,16 @@ package selector
 
 import (
 	"context"
+	"fmt"
 	backendbase "go-terraform-registry/internal/backend"
 	badgerdbbackend "go-terraform-registry/internal/backend/badgerdb_backend"
 	dynamodbbackend "go-terraform-registry/internal/backend/dynamodb_backend"
 	postgresbackend "go-terraform-registry/internal/backend/postgres_backend"
 	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/logging"
 	"go-terraform-registry/internal/storage"
 	"go-terraform-registry/internal/storage/local_storage"
 	"go-terraform-registry/internal/storage/s3_storage"
-	"log"
 )
 
 func SelectBackend(ctx context.Context, config config.RegistryConfig) *backendbase.Backend {
@@ -25,10 +26,10 @@ func SelectBackend(ctx context.Context, config config.RegistryConfig) *backendba
 	case "postgres":
 		selected, err = postgresbackend.NewPostgresBackend(ctx, config)
 	default:
-		selected, err = badgerdbbackend.NewBadgerDBBackend(ctx, config)
+		err = fmt.Errorf("unknown backend %q", config.Backend)
 	}
 	if err != nil {
-		log.Fatal(err)
+		logging.Fatal("Error selecting backend", "backend", config.Backend, "error", err)
 	}
 
 	return selected
@@ -43,7 +44,7 @@ func SelectStorage(ctx context.Context, config config.RegistryConfig) storage.Re
 	case "local":
 		selected = local_storage.NewLocalStorage(config)
 	default:
-		selected = local_storage.NewLocalStorage(config)
+		logging.Fatal("Error selecting storage backend", "storage_backend", config.StorageBackend)
 	}
 
 	_ = selected.ConfigureStorage(ctx)
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	backends        = []string{"badgerdb", "dynamodb", "postgres"}
+	storageBackends = []string{"local", "s3"}
+	tlsClientAuths  = []string{"", "none", "optional", "required"}
+	logFormats      = []string{"", "json", "text"}
+	rateLimitStores = []string{"memory", "postgres"}
+
+	hmacSigningMethods       = []string{"", "HS256", "HS384", "HS512"}
+	asymmetricSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
+// Validate reports every setting that would keep the registry from starting
+// or make it run differently than configured.
+func (c RegistryConfig) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if !slices.Contains(backends, c.Backend) {
+		invalid("unknown backend %q (BACKEND), expected one of %s", c.Backend, strings.Join(backends, ", "))
+	}
+	if c.Backend == "postgres" && c.Postgres.DatabaseURL == "" {
+		invalid("postgres.database_url (DATABASE_URL) is required for the postgres backend")
+	}
+	if c.Backend == "badgerdb" && c.BadgerDB.Path == "" {
+		invalid("badgerdb.path (BADGER_DB_PATH) is required for the badgerdb backend")
+	}
+
+	if !slices.Contains(storageBackends, c.StorageBackend) {
+		invalid("unknown storage backend %q (STORAGE_BACKEND), expected one of %s", c.StorageBackend, strings.Join(storageBackends, ", "))
+	}
+	if c.StorageBackend == "s3" && c.S3.BucketName == "" {
+		invalid("s3.bucket_name (S3_BUCKET_NAME) is required for the s3 storage backend")
+	}
+	if c.StorageBackend == "local" && c.LocalStorage.AssetsEndpoint == "" {
+		invalid("local_storage.assets_endpoint (LOCAL_STORAGE_ASSETS_ENDPOINT) is required for the local storage backend")
+	}
+
+	switch {
+	case slices.Contains(hmacSigningMethods, c.TokenSigningMethod):
+		if c.TokenEncryptionKey == "" {
+			invalid("token_encryption_key (TOKEN_ENCRYPTION_KEY) is required to sign tokens with %s", signingMethodName(c.TokenSigningMethod))
+		}
+	case slices.Contains(asymmetricSigningMethods, c.TokenSigningMethod):
+		if c.TokenSigningKeyFile == "" {
+			invalid("token_signing_key_file (TOKEN_SIGNING_KEY_FILE) is required to sign tokens with %s", c.TokenSigningMethod)
+		}
+	default:
+		invalid("unsupported token signing method %q (TOKEN_SIGNING_METHOD)", c.TokenSigningMethod)
+	}
+
+	if c.TLSCertFile != "" && c.TLSKeyFile == "" {
+		invalid("tls_key_file (TLS_KEY_FILE) is required when tls_cert_file is set")
+	}
+	if c.TLSCertFile == "" && c.TLSKeyFile != "" {
+		invalid("tls_cert_file (TLS_CERT_FILE) is required when tls_key_file is set")
+	}
+	if !slices.Contains(tlsClientAuths, c.TLSClientAuth) {
+		invalid("unsupported tls_client_auth %q (TLS_CLIENT_AUTH)", c.TLSClientAuth)
+	}
+	if (c.TLSClientAuth == "optional" || c.TLSClientAuth == "required") && c.TLSClientCAFile == "" {
+		invalid("tls_client_ca_file (TLS_CLIENT_CA_FILE) is required for client certificate authentication")
+	}
+
+	if !slices.Contains(rateLimitStores, c.RateLimit.Store) {
+		invalid("unknown rate_limit.store %q (RATE_LIMIT_STORE), expected one of %s", c.RateLimit.Store, strings.Join(rateLimitStores, ", "))
+	}
+	if c.RateLimit.Store == "postgres" && c.Backend != "postgres" {
+		invalid("rate_limit.store postgres requires the postgres backend")
+	}
+	if c.RateLimit.TrustForwardedFor && c.RateLimit.TrustedProxies < 1 {
+		invalid("rate_limit.trusted_proxies (RATE_LIMIT_TRUSTED_PROXIES) must be positive when forwarded addresses are trusted")
+	}
+	budgets := map[string]RateLimitBudget{
+		"reads":   c.RateLimit.Reads,
+		"uploads": c.RateLimit.Uploads,
+		"writes":  c.RateLimit.Writes,
+	}
+	for _, name := range slices.Sorted(maps.Keys(budgets)) {
+		if budgets[name].Rate < 0 || budgets[name].Burst < 0 {
+			invalid("rate_limit.%s rate and burst must not be negative", name)
+		}
+	}
+
+	if c.ReadCache.TTL < 0 {
+		invalid("read_cache.ttl (READ_CACHE_TTL) must not be negative")
+	}
+	if c.ReadCache.TTL > 0 && c.ReadCache.MaxEntries < 1 {
+		invalid("read_cache.max_entries (READ_CACHE_MAX_ENTRIES) must be positive when the read cache is enabled")
+	}
+
+	if c.OauthClientID != "" && (c.OauthClientSecret == "" || c.OauthClientRedirectURL == "") {
+		invalid("oauth_client_secret and oauth_client_redirect_url are required when oauth_client_id is set")
+	}
+
+	if c.ListenAddress == "" {
+		invalid("listen_address (LISTEN_ADDRESS) is required")
+	}
+	if c.AdminAddress == "" {
+		invalid("admin_address (ADMIN_ADDRESS) is required")
+	}
+	if c.ListenAddress != "" && c.ListenAddress == c.AdminAddress {
+		invalid("listen_address and admin_address must differ, both are %s", c.ListenAddress)
+	}
+	if c.Organization == "" {
+		invalid("default_organization (DEFAULT_ORGANIZATION) is required")
+	}
+
+	if !slices.Contains(logFormats, strings.ToLower(c.LogFormat)) {
+		invalid("unsupported log_format %q (LOG_FORMAT)", c.LogFormat)
+	}
+	var level slog.Level
+	if c.LogLevel != "" && level.UnmarshalText([]byte(c.LogLevel)) != nil {
+		invalid("unsupported log_level %q (LOG_LEVEL)", c.LogLevel)
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		invalid("tracing_sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1")
+	}
+
+	positive := map[string]int{
+		"webhook_max_attempts (WEBHOOK_MAX_ATTEMPTS)": c.WebhookMaxAttempts,
+		"webhook_workers (WEBHOOK_WORKERS)":           c.WebhookWorkers,
+	}
+	for _, name := range slices.Sorted(maps.Keys(positive)) {
+		if positive[name] < 1 {
+			invalid("%s must be positive", name)
+		}
+	}
+
+	durations := map[string]time.Duration{
+		"idle_timeout (IDLE_TIMEOUT)":               c.IdleTimeout,
+		"read_header_timeout (READ_HEADER_TIMEOUT)": c.ReadHeaderTimeout,
+		"read_timeout (READ_TIMEOUT)":               c.ReadTimeout,
+		"shutdown_timeout (SHUTDOWN_TIMEOUT)":       c.ShutdownTimeout,
+		"webhook_timeout (WEBHOOK_TIMEOUT)":         c.WebhookTimeout,
+		"write_timeout (WRITE_TIMEOUT)":             c.WriteTimeout,
+	}
+	for _, name := range slices.Sorted(maps.Keys(durations)) {
+		if durations[name] <= 0 {
+			invalid("%s must be positive", name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func signingMethodName(method string) string {
+	if method == "" {
+		return "HS256"
+	}
+	return method
+}
@@ -4,20 +4,35 @@ import (
 	"context"
 	"github.com/go-chi/chi/v5"
 	"go-terraform-registry/internal/api"
+	"go-terraform-registry/internal/audit"
 	"go-terraform-registry/internal/auth"
 	"go-terraform-registry/internal/backend"
 	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/eventstream"
+	"go-terraform-registry/internal/logging"
+	"go-terraform-registry/internal/ratelimit"
 	"go-terraform-registry/internal/response"
 	"go-terraform-registry/internal/storage"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
 	"net/http"
 	"strings"
 )
 
 type APIController struct {
-	Config  registryconfig.RegistryConfig
-	Backend backend.Backend
-	Storage storage.RegistryProviderStorage
-	Chi     *chi.Mux
+	Config       registryconfig.RegistryConfig
+	Backend      backend.Backend
+	Storage      storage.RegistryProviderStorage
+	Chi          *chi.Mux
+	OIDC         *registryconfig.Live[*auth.OIDCVerifier]
+	Keys         *registryconfig.Live[*auth.TokenKeys]
+	StaticTokens *registryconfig.Live[*auth.StaticTokenStore]
+	Certificates *auth.ClientCertificateMapper
+	Audit        *audit.Recorder
+	Events       *events.Bus
+	Broker       *eventstream.Broker
+	RateLimits   *ratelimit.Limiter
 }
 
 type RegistryAPIController interface {
@@ -25,59 +40,163 @@ type RegistryAPIController interface {
 	AuthenticateRequestMiddleware(next http.Handler) http.Handler
 }
 
-func NewAPIController(config registryconfig.RegistryConfig, backend backend.Backend, storage storage.RegistryProviderStorage) RegistryAPIController {
+func NewAPIController(live *registryconfig.Reloader, backend backend.Backend, storage storage.RegistryProviderStorage, bus *events.Bus, broker *eventstream.Broker, limits *ratelimit.Limiter) RegistryAPIController {
+	config := live.Current()
 	ac := &APIController{
-		Config:  config,
-		Backend: backend,
-		Storage: storage,
+		Config:     config,
+		Backend:    backend,
+		Storage:    storage,
+		Audit:      audit.NewRecorder(backend.AuditBackend),
+		Events:     bus,
+		Broker:     broker,
+		RateLimits: limits,
 	}
 
+	keys, err := registryconfig.NewLive(live, auth.LoadTokenKeys)
+	if err != nil {
+		logging.Fatal("Error loading token keys", "error", err)
+	}
+	ac.Keys = keys
+
+	staticTokens, err := registryconfig.NewLive(live, auth.LoadStaticTokenStore)
+	if err != nil {
+		logging.Fatal("Error loading static tokens", "error", err)
+	}
+	ac.StaticTokens = staticTokens
+
+	certificates, err := auth.LoadClientCertificateMapper(config)
+	if err != nil {
+		logging.Fatal("Error loading client certificate identities", "error", err)
+	}
+	ac.Certificates = certificates
+
+	oidc, err := registryconfig.NewLive(live, auth.LoadOIDCVerifier)
+	if err != nil {
+		logging.Fatal("Error loading OIDC configuration", "error", err)
+	}
+	ac.OIDC = oidc
+
 	return ac
 }
 
 func (a *APIController) CreateEndpoints(cr *chi.Mux) {
 	cr.Route("/api", func(r chi.Router) {
 		r.Use(a.AuthenticateRequestMiddleware)
+		r.Use(a.RateLimits.Middleware(ratelimit.Writes))
+
+		accountAPI := api.AccountAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.Get("/v2/ping", api.Ping)
+		r.Get("/v2/account/details", accountAPI.Details)
+
+		organizationsAPI := api.OrganizationsAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.Get("/v2/organizations", organizationsAPI.List)
+		r.With(a.Audit.Middleware("organization.create")).Post("/v2/organizations", organizationsAPI.Create)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}", organizationsAPI.Get)
+		r.With(a.Audit.Middleware("organization.update"), a.ValidateOrganizationMiddleware).Patch("/v2/organizations/{organization}", organizationsAPI.Update)
+		r.With(a.Audit.Middleware("organization.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}", organizationsAPI.Delete)
 
 		providerVersionsAPI := api.ProviderVersionsAPI{
 			Config:  a.Config,
 			Backend: a.Backend,
 			Storage: a.Storage,
+			Events:  a.Events,
 		}
-		r.With(ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions", providerVersionsAPI.CreateVersion)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions", providerVersionsAPI.ListVersions)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}", providerVersionsAPI.GetVersion)
-		r.With(ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}", providerVersionsAPI.DeleteVersion)
-		r.With(ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms", providerVersionsAPI.CreatePlatform)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms", providerVersionsAPI.ListPlatform)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms/{os}/{arch}", providerVersionsAPI.GetPlatform)
-		r.With(ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms/{os}/{arch}", providerVersionsAPI.DeletePlatform)
+		r.With(a.Audit.Middleware("provider-version.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions", providerVersionsAPI.CreateVersion)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions", providerVersionsAPI.ListVersions)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}", providerVersionsAPI.GetVersion)
+		r.With(a.Audit.Middleware("provider-version.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}", providerVersionsAPI.DeleteVersion)
+		r.With(a.Audit.Middleware("provider-platform.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms", providerVersionsAPI.CreatePlatform)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms", providerVersionsAPI.ListPlatform)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms/{os}/{arch}", providerVersionsAPI.GetPlatform)
+		r.With(a.Audit.Middleware("provider-platform.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/versions/{version}/platforms/{os}/{arch}", providerVersionsAPI.DeletePlatform)
 
 		providersAPI := api.ProvidersAPI{
 			Config:  a.Config,
 			Backend: a.Backend,
 			Storage: a.Storage,
 		}
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers", providersAPI.List)
-		r.With(ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers", providersAPI.Create)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}", providersAPI.Get)
-		r.With(ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}", providersAPI.Delete)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers", providersAPI.List)
+		r.With(a.Audit.Middleware("provider.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-providers", providersAPI.Create)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}", providersAPI.Get)
+		r.With(a.Audit.Middleware("provider.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}", providersAPI.Delete)
 
 		modulesAPI := api.ModulesAPI{
 			Config:  a.Config,
 			Backend: a.Backend,
 			Storage: a.Storage,
 		}
-		r.With(ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-modules", modulesAPI.Create)
-		r.With(ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}", modulesAPI.Get)
+		r.With(a.Audit.Middleware("module.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-modules", modulesAPI.Create)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}", modulesAPI.Get)
 
 		moduleVersionsAPI := api.ModuleVersionsAPI{
 			Config:  a.Config,
 			Backend: a.Backend,
 			Storage: a.Storage,
+			Events:  a.Events,
+		}
+		r.With(a.Audit.Middleware("module-version.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/versions", moduleVersionsAPI.Create)
+		r.With(a.Audit.Middleware("module-version.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/{version}", moduleVersionsAPI.Delete)
+
+		downloadsAPI := api.DownloadsAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
 		}
-		r.With(ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/versions", moduleVersionsAPI.Create)
-		r.With(ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/{version}", moduleVersionsAPI.Delete)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-providers/{registry}/{namespace}/{name}/downloads", downloadsAPI.Provider)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-modules/{registry}/{namespace}/{name}/{provider}/downloads", downloadsAPI.Module)
+
+		namespacesAPI := api.NamespacesAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-namespaces/{namespace}", namespacesAPI.Get)
+		r.With(a.Audit.Middleware("namespace.update"), a.ValidateOrganizationMiddleware).Patch("/v2/organizations/{organization}/registry-namespaces/{namespace}", namespacesAPI.Update)
+
+		auditAPI := api.AuditAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/audit-trail", auditAPI.List)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/audit-trail/export", auditAPI.Export)
+
+		webhooksAPI := api.WebhooksAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/webhooks", webhooksAPI.List)
+		r.With(a.Audit.Middleware("webhook.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/webhooks", webhooksAPI.Create)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/webhooks/{webhook}", webhooksAPI.Get)
+		r.With(a.Audit.Middleware("webhook.update"), a.ValidateOrganizationMiddleware).Patch("/v2/organizations/{organization}/webhooks/{webhook}", webhooksAPI.Update)
+		r.With(a.Audit.Middleware("webhook.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/webhooks/{webhook}", webhooksAPI.Delete)
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/webhooks/{webhook}/deliveries", webhooksAPI.Deliveries)
+
+		eventsAPI := api.EventsAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+			Broker:  a.Broker,
+		}
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/events", eventsAPI.Stream)
+
+		sharesAPI := api.SharesAPI{
+			Config:  a.Config,
+			Backend: a.Backend,
+			Storage: a.Storage,
+		}
+		r.With(a.ValidateOrganizationMiddleware).Get("/v2/organizations/{organization}/registry-shares", sharesAPI.List)
+		r.With(a.Audit.Middleware("share.create"), a.ValidateOrganizationMiddleware).Post("/v2/organizations/{organization}/registry-shares", sharesAPI.Create)
+		r.With(a.Audit.Middleware("share.delete"), a.ValidateOrganizationMiddleware).Delete("/v2/organizations/{organization}/registry-shares/{share}", sharesAPI.Delete)
 
 		gpgKeysAPI := api.GPGKeysAPI{
 			Config:  a.Config,
@@ -85,10 +204,10 @@ func (a *APIController) CreateEndpoints(cr *chi.Mux) {
 			Storage: a.Storage,
 		}
 		r.Get("/registry/{registry}/v2/gpg-keys", gpgKeysAPI.List)
-		r.Post("/registry/{registry}/v2/gpg-keys", gpgKeysAPI.Add)
+		r.With(a.Audit.Middleware("gpg-key.create")).Post("/registry/{registry}/v2/gpg-keys", gpgKeysAPI.Add)
 		r.Get("/registry/{registry}/v2/gpg-keys/{namespace}/{key_id}", gpgKeysAPI.Get)
-		r.Patch("/registry/{registry}/v2/gpg-keys/{namespace}/{key_id}", gpgKeysAPI.Update)
-		r.Delete("/registry/{registry}/v2/gpg-keys/{namespace}/{key_id}", gpgKeysAPI.Delete)
+		r.With(a.Audit.Middleware("gpg-key.update")).Patch("/registry/{registry}/v2/gpg-keys/{namespace}/{key_id}", gpgKeysAPI.Update)
+		r.With(a.Audit.Middleware("gpg-key.delete")).Delete("/registry/{registry}/v2/gpg-keys/{namespace}/{key_id}", gpgKeysAPI.Delete)
 	})
 }
 
@@ -96,6 +215,13 @@ func (a *APIController) AuthenticateRequestMiddleware(next http.Handler) http.Ha
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
+			if a.Certificates != nil {
+				if identity, ok := a.Certificates.Identify(r); ok {
+					serveIdentity(w, r, next, *identity)
+					return
+				}
+			}
+
 			response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
 				Error: "Missing Authorization header",
 			})
@@ -111,7 +237,44 @@ func (a *APIController) AuthenticateRequestMiddleware(next http.Handler) http.Ha
 		}
 
 		tokenString := authHeader[len(prefix):]
-		token, err := auth.GetJWTClaimsToken(tokenString, []byte(a.Config.TokenEncryptionKey))
+		if staticTokens := a.StaticTokens.Get(); staticTokens != nil && !auth.IsJWT(tokenString) {
+			entry, ok := staticTokens.Lookup(tokenString)
+			if !ok {
+				response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+					Error: "Invalid token",
+				})
+				return
+			}
+
+			serveIdentity(w, r, next, entry.Identity())
+			return
+		}
+
+		if oidc := a.OIDC.Get(); oidc != nil && oidc.Handles(tokenString) {
+			identity, err := oidc.Verify(r.Context(), tokenString)
+			if err != nil {
+				slog.Info("Rejected OIDC token", "error", err)
+				response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+					Error: "Invalid token",
+				})
+				return
+			}
+
+			if !identity.Permits(r.Method, r.URL.Path) {
+				response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+					Error: "Token is only permitted to publish to its namespaces",
+				})
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "organization", []string{identity.Organization})
+			ctx = context.WithValue(ctx, "namespaces", identity.Namespaces)
+			ctx = context.WithValue(ctx, "login", identity.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		token, err := auth.GetJWTClaimsToken(tokenString, a.Keys.Get())
 		if err != nil {
 			response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
 				Error: err.Error(),
@@ -122,13 +285,32 @@ func (a *APIController) AuthenticateRequestMiddleware(next http.Handler) http.Ha
 		if token != nil && token.Valid {
 			if claims, ok := token.Claims.(*auth.RegistryClaims); ok {
 				ctx := context.WithValue(r.Context(), "organization", claims.Organization)
+				if login, ok := claims.MapClaims["login"].(string); ok {
+					ctx = context.WithValue(ctx, "login", login)
+				}
 				next.ServeHTTP(w, r.WithContext(ctx))
+				return
 			}
 		}
+
+		response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+			Error: "Invalid token",
+		})
 	})
 }
 
-func ValidateOrganizationMiddleware(next http.Handler) http.Handler {
+func serveIdentity(w http.ResponseWriter, r *http.Request, next http.Handler, identity auth.Identity) {
+	if !identity.Permits(r.Method) {
+		response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+			Error: "Identity is read only",
+		})
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+}
+
+func (a *APIController) ValidateOrganizationMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		organizationParam := chi.URLParam(r, "organization")
 		orgVal := r.Context().Value("organization")
@@ -149,6 +331,33 @@ func ValidateOrganizationMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
+		if namespaces, ok := r.Context().Value("namespaces").([]string); ok {
+			namespaceParam := chi.URLParam(r, "namespace")
+			if namespaceParam != "" && !containsIgnoreCase(namespaces, namespaceParam) {
+				response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+					Error: "Token is not permitted to publish to namespace",
+				})
+				return
+			}
+		}
+
+		parameters := registrytypes.APIParameters{
+			Organization: organizationParam,
+		}
+		organization, err := a.Backend.OrganizationsGet(r.Context(), parameters)
+		if err != nil {
+			response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		if organization == nil {
+			response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
+				Error: "Organization not found",
+			})
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
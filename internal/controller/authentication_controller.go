@@ -1,33 +1,53 @@
 package controller
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	registryauth "go-terraform-registry/internal/auth"
 	registryconfig "go-terraform-registry/internal/config"
 	"go-terraform-registry/internal/githubclient"
+	"go-terraform-registry/internal/logging"
 	"go-terraform-registry/internal/response"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 type AuthenticationController struct {
-	Config      registryconfig.RegistryConfig
-	OauthConfig *oauth2.Config
+	Config         registryconfig.RegistryConfig
+	OauthConfig    *registryconfig.Live[*oauth2.Config]
+	Keys           *registryconfig.Live[*registryauth.TokenKeys]
+	Authorizations *registryauth.AuthorizationStore
 }
 
+const (
+	terraformClientID = "terraform-cli"
+	stateCookieName   = "oauth-state"
+)
+
 type RegistryAuthenticationController interface {
 	Authorization(http.ResponseWriter, *http.Request)
 	Callback(http.ResponseWriter, *http.Request)
 	AccessToken(http.ResponseWriter, *http.Request)
 }
 
-func NewAuthenticationController(router chi.Router, config registryconfig.RegistryConfig) RegistryAuthenticationController {
+func NewAuthenticationController(router chi.Router, live *registryconfig.Reloader) RegistryAuthenticationController {
+	config := live.Current()
 	ac := &AuthenticationController{
-		Config: config,
+		Config:         config,
+		Authorizations: registryauth.NewAuthorizationStore(),
+	}
+
+	keys, err := registryconfig.NewLive(live, registryauth.LoadTokenKeys)
+	if err != nil {
+		logging.Fatal("Error loading token keys", "error", err)
 	}
+	ac.Keys = keys
 
 	endpoint := github.Endpoint
 	if config.GitHubEndpoint != "" {
@@ -37,15 +57,20 @@ func NewAuthenticationController(router chi.Router, config registryconfig.Regist
 		}
 	}
 
-	log.Printf("Authorization endpoint: %s", endpoint.AuthURL)
-	log.Printf("Token endpoint: %s", endpoint.TokenURL)
+	slog.Info("OAuth endpoints", "authorization", endpoint.AuthURL, "token", endpoint.TokenURL)
 
-	ac.OauthConfig = &oauth2.Config{
-		ClientID:     config.OauthClientID,
-		ClientSecret: config.OauthClientSecret,
-		RedirectURL:  config.OauthClientRedirectURL,
-		Scopes:       []string{"user"},
-		Endpoint:     endpoint,
+	// The client credentials can be rotated by reloading the configuration
+	ac.OauthConfig, err = registryconfig.NewLive(live, func(c registryconfig.RegistryConfig) (*oauth2.Config, error) {
+		return &oauth2.Config{
+			ClientID:     c.OauthClientID,
+			ClientSecret: c.OauthClientSecret,
+			RedirectURL:  c.OauthClientRedirectURL,
+			Scopes:       []string{"user"},
+			Endpoint:     endpoint,
+		}, nil
+	})
+	if err != nil {
+		logging.Fatal("Error configuring OAuth", "error", err)
 	}
 
 	router.Route("/oauth", func(r chi.Router) {
@@ -58,81 +83,200 @@ func NewAuthenticationController(router chi.Router, config registryconfig.Regist
 }
 
 func (a *AuthenticationController) Authorization(w http.ResponseWriter, r *http.Request) {
-	state := r.URL.Query().Get("state")
-	url := a.OauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	query := r.URL.Query()
+	clientID := query.Get("client_id")
+	redirectURI := query.Get("redirect_uri")
+
+	if clientID != terraformClientID {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "invalid client_id",
+		})
+		return
+	}
+
+	// Errors are only reported back to the redirect uri once it is known to be safe
+	if !registryauth.IsLoopbackRedirect(redirectURI) {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "redirect_uri must be a loopback address",
+		})
+		return
+	}
+
+	clientState := query.Get("state")
+	if query.Get("response_type") != "code" {
+		authorizationError(w, r, redirectURI, clientState, "unsupported_response_type")
+		return
+	}
+
+	codeChallenge := query.Get("code_challenge")
+	if codeChallenge == "" || query.Get("code_challenge_method") != "S256" {
+		authorizationError(w, r, redirectURI, clientState, "invalid_request")
+		return
+	}
+
+	state, err := a.Authorizations.AddPending(registryauth.PendingAuthorization{
+		ClientID:      clientID,
+		ClientState:   clientState,
+		RedirectURI:   redirectURI,
+		CodeChallenge: codeChallenge,
+		ExpiresAt:     time.Now().Add(registryauth.MaxPendingLifetime),
+	})
+	if errors.Is(err, registryauth.ErrTooManyAuthorizations) {
+		slog.Warn("Rejected authorization", "error", err)
+		authorizationError(w, r, redirectURI, clientState, "temporarily_unavailable")
+		return
+	}
+	if err != nil {
+		authorizationError(w, r, redirectURI, clientState, "server_error")
+		return
+	}
 
-	redirectURL := r.URL.Query().Get("redirect_uri")
 	http.SetCookie(w, &http.Cookie{
-		Name:     "redirect-uri",
-		Value:    redirectURL,
-		Path:     "/",
-		MaxAge:   300,
+		Name:     stateCookieName,
+		Value:    state,
+		Path:     "/oauth",
+		MaxAge:   600,
 		HttpOnly: true,
 		Secure:   true,
 		SameSite: http.SameSiteLaxMode,
 	})
-	http.Redirect(w, r, url, http.StatusFound)
+	http.Redirect(w, r, a.OauthConfig.Get().AuthCodeURL(state), http.StatusFound)
 }
 
 func (a *AuthenticationController) Callback(w http.ResponseWriter, r *http.Request) {
-	state := r.URL.Query().Get("state")
-	code := r.URL.Query().Get("code")
+	query := r.URL.Query()
+	state := query.Get("state")
 
-	redirectUrl, err := r.Cookie("redirect-uri")
-	if err != nil {
-		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
-			Error: "Failed to get redirect uri",
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "invalid state",
 		})
 		return
 	}
 
-	redirectURL := fmt.Sprintf("%s?code=%s&state=%s", redirectUrl, code, state)
-	http.Redirect(w, r, redirectURL, http.StatusFound)
-}
-
-func (a *AuthenticationController) AccessToken(w http.ResponseWriter, r *http.Request) {
-	err := r.ParseForm()
-	if err != nil {
+	pending, ok := a.Authorizations.TakePending(state)
+	if !ok {
 		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
-			Error: err.Error(),
+			Error: "authorization request expired or already used",
 		})
 		return
 	}
 
-	code := r.FormValue("code")
-	token, err := a.OauthConfig.Exchange(r.Context(), code)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    "",
+		Path:     "/oauth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if errorCode := query.Get("error"); errorCode != "" {
+		authorizationError(w, r, pending.RedirectURI, pending.ClientState, "access_denied")
+		return
+	}
+
+	token, err := a.OauthConfig.Get().Exchange(r.Context(), query.Get("code"))
 	if err != nil {
-		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
-			Error: "Failed to exchange token",
-		})
+		slog.WarnContext(r.Context(), "Failed to exchange token", "error", err)
+		authorizationError(w, r, pending.RedirectURI, pending.ClientState, "server_error")
 		return
 	}
 
 	client, err := githubclient.NewClient(r.Context(), token.AccessToken, a.Config.GitHubEndpoint)
 	if err != nil {
-		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
-			Error: "Unable to create GitHub client connection",
-		})
+		authorizationError(w, r, pending.RedirectURI, pending.ClientState, "server_error")
 		return
 	}
 
 	userName, err := registryauth.GetGitHubUserName(r.Context(), client, token.AccessToken)
 	if err != nil {
-		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
-			Error: "Failed to get GitHub user name",
+		authorizationError(w, r, pending.RedirectURI, pending.ClientState, "server_error")
+		return
+	}
+
+	code, err := a.Authorizations.AddIssued(registryauth.IssuedAuthorization{
+		ClientID:      pending.ClientID,
+		RedirectURI:   pending.RedirectURI,
+		CodeChallenge: pending.CodeChallenge,
+		Login:         *userName,
+		ExpiresAt:     time.Now().Add(registryauth.MaxIssuedLifetime),
+	})
+	if err != nil {
+		authorizationError(w, r, pending.RedirectURI, pending.ClientState, "server_error")
+		return
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	params.Set("state", pending.ClientState)
+	http.Redirect(w, r, appendQuery(pending.RedirectURI, params), http.StatusFound)
+}
+
+func (a *AuthenticationController) AccessToken(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "invalid_request",
+		})
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "unsupported_grant_type",
+		})
+		return
+	}
+
+	issued, ok := a.Authorizations.TakeIssued(r.PostForm.Get("code"))
+	if !ok ||
+		issued.ClientID != r.PostForm.Get("client_id") ||
+		issued.RedirectURI != r.PostForm.Get("redirect_uri") ||
+		!registryauth.VerifyPKCE(r.PostForm.Get("code_verifier"), issued.CodeChallenge) {
+		response.JsonResponse(w, http.StatusBadRequest, response.ErrorResponse{
+			Error: "invalid_grant",
 		})
 		return
 	}
 
-	accessToken, err := registryauth.CreateJWTToken(*userName, []byte(a.Config.TokenEncryptionKey))
+	accessToken, err := registryauth.CreateJWTToken(issued.Login, a.Keys.Get())
 	if err != nil {
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
-			Error: "Failed to create access token",
+			Error: "server_error",
 		})
 		return
 	}
 
+	w.Header().Set("Cache-Control", "no-store")
 	response.JsonResponse(w, http.StatusOK, response.AccessTokenResponse{
-		Token: *accessToken,
+		Token:     *accessToken,
+		TokenType: "bearer",
 	})
 }
+
+func authorizationError(w http.ResponseWriter, r *http.Request, redirectURI string, state string, errorCode string) {
+	params := url.Values{}
+	params.Set("error", errorCode)
+	if state != "" {
+		params.Set("state", state)
+	}
+	http.Redirect(w, r, appendQuery(redirectURI, params), http.StatusFound)
+}
+
+func appendQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,315 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	registryauth "go-terraform-registry/internal/auth"
+	registryconfig "go-terraform-registry/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testVerifier    = "test-verifier-test-verifier-test-verifier-0123"
+	testRedirectURI = "http://localhost:10000/login"
+	testLogin       = "octocat"
+)
+
+// fakeGitHub stands in for the GitHub authorize, token and user endpoints.
+func fakeGitHub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimLeft(r.URL.Path, "/") {
+		case "login/oauth/authorize":
+			query := r.URL.Query()
+			callback := query.Get("redirect_uri") + "?" + url.Values{
+				"code":  {"github-code"},
+				"state": {query.Get("state")},
+			}.Encode()
+			http.Redirect(w, r, callback, http.StatusFound)
+		case "login/oauth/access_token":
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "github-code" {
+				http.Error(w, "bad_verification_code", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"github-token","token_type":"bearer"}`))
+		case "api/v3/user":
+			if r.Header.Get("Authorization") != "Bearer github-token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"login":"` + testLogin + `"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+type oauthTest struct {
+	t          *testing.T
+	registry   *httptest.Server
+	github     *httptest.Server
+	controller *AuthenticationController
+	client     *http.Client
+}
+
+func newOAuthTest(t *testing.T) *oauthTest {
+	t.Helper()
+
+	github := fakeGitHub(t)
+	t.Cleanup(github.Close)
+
+	router := chi.NewRouter()
+	registry := httptest.NewServer(router)
+	t.Cleanup(registry.Close)
+
+	config := registryconfig.RegistryConfig{
+		GitHubEndpoint:         github.URL + "/",
+		OauthClientID:          "client-id",
+		OauthClientSecret:      "client-secret",
+		OauthClientRedirectURL: registry.URL + "/oauth/callback",
+		TokenEncryptionKey:     "test-encryption-key",
+	}
+	controller := NewAuthenticationController(router, registryconfig.NewReloader("", config)).(*AuthenticationController)
+
+	return &oauthTest{
+		t:          t,
+		registry:   registry,
+		github:     github,
+		controller: controller,
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (o *oauthTest) get(rawURL string, cookies ...*http.Cookie) *http.Response {
+	o.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		o.t.Fatalf("NewRequest() error = %v", err)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		o.t.Fatalf("GET %s error = %v", rawURL, err)
+	}
+	o.t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func (o *oauthTest) authorize(redirectURI string) *http.Response {
+	o.t.Helper()
+
+	sum := sha256.Sum256([]byte(testVerifier))
+	query := url.Values{
+		"client_id":             {terraformClientID},
+		"redirect_uri":          {redirectURI},
+		"response_type":         {"code"},
+		"state":                 {"client-state"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	return o.get(o.registry.URL + "/oauth/authorization?" + query.Encode())
+}
+
+// login runs the browser part of the flow and returns the registry code
+// handed to terraform.
+func (o *oauthTest) login() string {
+	o.t.Helper()
+
+	resp := o.authorize(testRedirectURI)
+	if resp.StatusCode != http.StatusFound {
+		o.t.Fatalf("authorization status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+	cookie := stateCookie(o.t, resp)
+
+	// GitHub sends the browser back to the registry callback
+	resp = o.get(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound {
+		o.t.Fatalf("github authorize status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	resp = o.get(resp.Header.Get("Location"), cookie)
+	if resp.StatusCode != http.StatusFound {
+		o.t.Fatalf("callback status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		o.t.Fatalf("callback location error = %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != testRedirectURI {
+		o.t.Fatalf("callback redirected to %s, want %s", got, testRedirectURI)
+	}
+	if got := location.Query().Get("state"); got != "client-state" {
+		o.t.Fatalf("callback state = %q, want the client state", got)
+	}
+
+	code := location.Query().Get("code")
+	if code == "" {
+		o.t.Fatalf("callback returned no code: %s", location)
+	}
+
+	return code
+}
+
+func (o *oauthTest) token(code string, verifier string) (*http.Response, map[string]string) {
+	o.t.Helper()
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"client_id":     {terraformClientID},
+		"redirect_uri":  {testRedirectURI},
+		"code_verifier": {verifier},
+	}
+
+	resp, err := o.client.PostForm(o.registry.URL+"/oauth/token", form)
+	if err != nil {
+		o.t.Fatalf("token request error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body := map[string]string{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		o.t.Fatalf("token response error = %v", err)
+	}
+
+	return resp, body
+}
+
+func stateCookie(t *testing.T, resp *http.Response) *http.Cookie {
+	t.Helper()
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == stateCookieName {
+			return cookie
+		}
+	}
+
+	t.Fatalf("authorization did not set the %s cookie", stateCookieName)
+	return nil
+}
+
+func TestOAuthFlow(t *testing.T) {
+	o := newOAuthTest(t)
+
+	resp, body := o.token(o.login(), testVerifier)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("token status = %d, want %d: %v", resp.StatusCode, http.StatusOK, body)
+	}
+
+	token, err := registryauth.GetJWTToken(body["access_token"], o.controller.Keys.Get())
+	if err != nil || !token.Valid {
+		t.Fatalf("access token is not valid: %v", err)
+	}
+	if login := token.Claims.(jwt.MapClaims)["login"]; login != testLogin {
+		t.Errorf("access token login = %v, want %s", login, testLogin)
+	}
+}
+
+func TestOAuthBadVerifier(t *testing.T) {
+	o := newOAuthTest(t)
+
+	resp, body := o.token(o.login(), strings.Repeat("x", len(testVerifier)))
+	if resp.StatusCode != http.StatusBadRequest || body["error"] != "invalid_grant" {
+		t.Errorf("token = %d %v, want invalid_grant", resp.StatusCode, body)
+	}
+}
+
+func TestOAuthReusedCode(t *testing.T) {
+	o := newOAuthTest(t)
+	code := o.login()
+
+	if resp, body := o.token(code, testVerifier); resp.StatusCode != http.StatusOK {
+		t.Fatalf("first token = %d %v, want %d", resp.StatusCode, body, http.StatusOK)
+	}
+	if resp, body := o.token(code, testVerifier); resp.StatusCode != http.StatusBadRequest || body["error"] != "invalid_grant" {
+		t.Errorf("second token = %d %v, want invalid_grant", resp.StatusCode, body)
+	}
+}
+
+func TestOAuthStateMismatch(t *testing.T) {
+	o := newOAuthTest(t)
+
+	resp := o.authorize(testRedirectURI)
+	cookie := stateCookie(t, resp)
+	callback := o.get(resp.Header.Get("Location")).Header.Get("Location")
+
+	tests := []struct {
+		name    string
+		cookies []*http.Cookie
+	}{
+		{"no cookie", nil},
+		{"other cookie", []*http.Cookie{{Name: stateCookieName, Value: "other-state"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := o.get(callback, tt.cookies...); resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("callback status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+
+	// The rejected attempts must not have used up the authorization
+	if resp := o.get(callback, cookie); resp.StatusCode != http.StatusFound {
+		t.Errorf("callback with the state cookie status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+}
+
+func TestOAuthNonLoopbackRedirect(t *testing.T) {
+	o := newOAuthTest(t)
+
+	for _, redirectURI := range []string{"http://example.com/login", "https://localhost/login", ""} {
+		t.Run(redirectURI, func(t *testing.T) {
+			resp := o.authorize(redirectURI)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("authorization status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+			if location := resp.Header.Get("Location"); location != "" {
+				t.Errorf("authorization redirected to %s", location)
+			}
+		})
+	}
+}
+
+func TestOAuthExpiredPending(t *testing.T) {
+	o := newOAuthTest(t)
+
+	state, err := o.controller.Authorizations.AddPending(registryauth.PendingAuthorization{
+		ClientID:    terraformClientID,
+		ClientState: "client-state",
+		RedirectURI: testRedirectURI,
+		ExpiresAt:   time.Now().Add(-time.Second),
+	})
+	if err != nil {
+		t.Fatalf("AddPending() error = %v", err)
+	}
+
+	callback := o.registry.URL + "/oauth/callback?" + url.Values{"code": {"github-code"}, "state": {state}}.Encode()
+	resp := o.get(callback, &http.Cookie{Name: stateCookieName, Value: state})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("callback status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/models"
+	"go-terraform-registry/internal/response"
+	"go-terraform-registry/internal/storage"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+	healthStatusError       = "error"
+
+	healthCheckTimeout = 5 * time.Second
+)
+
+type HealthController struct {
+	Backend backend.BackendLifecycle
+	Storage storage.RegistryProviderStorage
+	// Details adds the result of every check to the readiness response
+	Details bool
+}
+
+type RegistryHealthController interface {
+	Health(http.ResponseWriter, *http.Request)
+	Ready(http.ResponseWriter, *http.Request)
+}
+
+func NewHealthController(r chi.Router, backend backend.BackendLifecycle, storage storage.RegistryProviderStorage) RegistryHealthController {
+	hc := &HealthController{
+		Backend: backend,
+		Storage: storage,
+		Details: true,
+	}
+
+	r.Get("/healthz", hc.Health)
+	r.Get("/readyz", hc.Ready)
+
+	return hc
+}
+
+// NewPublicHealthController serves the probes on listeners reachable by
+// registry clients. Readiness only reports the overall status, the checks and
+// their errors are left to the admin listener.
+func NewPublicHealthController(r chi.Router, backend backend.BackendLifecycle, storage storage.RegistryProviderStorage) RegistryHealthController {
+	hc := &HealthController{
+		Backend: backend,
+		Storage: storage,
+	}
+
+	r.Get("/healthz", hc.Health)
+	r.Get("/readyz", hc.Ready)
+
+	return hc
+}
+
+// Health reports the process is up, it does not look at dependencies.
+func (h *HealthController) Health(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	response.JsonResponse(w, http.StatusOK, models.HealthResponse{
+		Status: healthStatusOK,
+	})
+}
+
+// Ready probes the backend and the storage concurrently and reports each of
+// them, it fails unless all of them are healthy.
+func (h *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]func(context.Context) error{
+		"backend": h.Backend.HealthCheck,
+		"storage": h.Storage.HealthCheck,
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	resp := models.HealthResponse{
+		Status: healthStatusOK,
+		Checks: map[string]models.HealthCheck{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check(ctx)
+			result := models.HealthCheck{
+				Status:     healthStatusOK,
+				DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				slog.WarnContext(r.Context(), "Readiness check failed", "check", name, "error", err)
+				result.Status = healthStatusError
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[name] = result
+			if err != nil {
+				resp.Status = healthStatusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if resp.Status != healthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	if !h.Details {
+		resp.Checks = nil
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JsonResponse(w, status, resp)
+}
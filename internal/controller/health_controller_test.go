@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/models"
+	"go-terraform-registry/internal/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type healthBackend struct {
+	backend.BackendLifecycle
+	err error
+}
+
+func (h healthBackend) HealthCheck(context.Context) error {
+	return h.err
+}
+
+type healthStorage struct {
+	storage.RegistryProviderStorage
+}
+
+func (healthStorage) HealthCheck(context.Context) error {
+	return nil
+}
+
+func TestReadiness(t *testing.T) {
+	failing := healthBackend{err: errors.New("dial tcp 10.0.0.5:5432: connection refused")}
+
+	tests := []struct {
+		name       string
+		controller func(chi.Router)
+		wantStatus int
+		wantChecks bool
+	}{
+		{"public ready", func(r chi.Router) { NewPublicHealthController(r, healthBackend{}, healthStorage{}) }, http.StatusOK, false},
+		{"public unavailable", func(r chi.Router) { NewPublicHealthController(r, failing, healthStorage{}) }, http.StatusServiceUnavailable, false},
+		{"admin unavailable", func(r chi.Router) { NewHealthController(r, failing, healthStorage{}) }, http.StatusServiceUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			tt.controller(router)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+
+			var resp models.HealthResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got := len(resp.Checks) > 0; got != tt.wantChecks {
+				t.Errorf("response has checks = %v, want %v", got, tt.wantChecks)
+			}
+			if !tt.wantChecks && strings.Contains(recorder.Body.String(), "10.0.0.5") {
+				t.Errorf("public readiness leaks the check error: %s", recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/auth"
+	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/logging"
+	"go-terraform-registry/internal/response"
+	"net/http"
+)
+
+type JWKSController struct {
+	Keys *registryconfig.Live[*auth.TokenKeys]
+}
+
+type RegistryJWKSController interface {
+	JWKS(http.ResponseWriter, *http.Request)
+}
+
+func NewJWKSController(r chi.Router, live *registryconfig.Reloader) RegistryJWKSController {
+	keys, err := registryconfig.NewLive(live, auth.LoadTokenKeys)
+	if err != nil {
+		logging.Fatal("Error loading token keys", "error", err)
+	}
+
+	jc := &JWKSController{
+		Keys: keys,
+	}
+
+	r.Get("/.well-known/jwks.json", jc.JWKS)
+
+	return jc
+}
+
+func (j *JWKSController) JWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JsonResponse(w, http.StatusOK, j.Keys.Get().JWKS())
+}
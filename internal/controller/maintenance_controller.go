@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/audit"
+	"go-terraform-registry/internal/backend"
+	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/maintenance"
+	"go-terraform-registry/internal/models"
+	"go-terraform-registry/internal/response"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const adminLogin = "admin"
+
+type MaintenanceController struct {
+	Mode         *maintenance.Mode
+	AdminToken   string
+	Organization string
+}
+
+type RegistryMaintenanceController interface {
+	Get(http.ResponseWriter, *http.Request)
+	Set(http.ResponseWriter, *http.Request)
+}
+
+func NewMaintenanceController(r chi.Router, config registryconfig.RegistryConfig, mode *maintenance.Mode, auditBackend backend.AuditBackend) RegistryMaintenanceController {
+	mc := &MaintenanceController{
+		Mode:         mode,
+		AdminToken:   config.AdminToken,
+		Organization: config.Organization,
+	}
+
+	r.Get("/maintenance", mc.Get)
+	r.With(mc.AuthenticateAdminMiddleware, audit.NewRecorder(auditBackend).Middleware("maintenance.update")).Put("/maintenance", mc.Set)
+
+	return mc
+}
+
+// AuthenticateAdminMiddleware requires the admin token when one is configured,
+// and a loopback client otherwise.
+func (m *MaintenanceController) AuthenticateAdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.AdminToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(m.AdminToken)) != 1 {
+				slog.WarnContext(r.Context(), "Rejected admin request", "path", r.URL.Path, "remote", r.RemoteAddr)
+				response.JsonResponse(w, http.StatusUnauthorized, response.ErrorResponse{
+					Error: "Invalid admin token",
+				})
+				return
+			}
+		} else if !loopback(r.RemoteAddr) {
+			slog.WarnContext(r.Context(), "Rejected admin request", "path", r.URL.Path, "remote", r.RemoteAddr)
+			response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+				Error: "Admin changes are only accepted from loopback without an admin token",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "login", adminLogin)))
+	})
+}
+
+func (m *MaintenanceController) Get(w http.ResponseWriter, _ *http.Request) {
+	m.respond(w)
+}
+
+// Set switches maintenance mode until it is switched again or the maintenance
+// configuration changes on reload. The change is audited under the default
+// organization, it applies to all of them.
+func (m *MaintenanceController) Set(w http.ResponseWriter, r *http.Request) {
+	var req models.MaintenanceRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		response.JsonResponse(w, http.StatusUnprocessableEntity, response.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	target := "maintenance/disabled"
+	if req.Enabled {
+		target = "maintenance/enabled"
+	}
+	audit.Annotate(r.Context(), m.Organization, target)
+
+	m.Mode.Set(req.Enabled, req.Message)
+	m.respond(w)
+}
+
+func (m *MaintenanceController) respond(w http.ResponseWriter) {
+	state := m.Mode.State()
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JsonResponse(w, http.StatusOK, models.MaintenanceResponse{
+		Enabled: state.Enabled,
+		Message: state.Message,
+	})
+}
+
+func loopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -2,20 +2,22 @@ package controller
 
 import (
 	"github.com/go-chi/chi/v5"
-	"go-terraform-registry/internal/auth"
 	"go-terraform-registry/internal/backend"
 	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/downloads"
+	"go-terraform-registry/internal/ratelimit"
 	"go-terraform-registry/internal/response"
 	"go-terraform-registry/internal/storage"
 	registrytypes "go-terraform-registry/internal/types"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type ModuleController struct {
-	Config  registryconfig.RegistryConfig
-	Backend backend.Backend
-	Storage storage.RegistryProviderStorage
+	Config    registryconfig.RegistryConfig
+	Backend   backend.Backend
+	Storage   storage.RegistryProviderStorage
+	Downloads *downloads.Recorder
 }
 
 type RegistryModuleController interface {
@@ -23,21 +25,19 @@ type RegistryModuleController interface {
 	Versions(http.ResponseWriter, *http.Request)
 }
 
-func NewModuleController(router chi.Router, config registryconfig.RegistryConfig, backend backend.Backend, storage storage.RegistryProviderStorage) RegistryModuleController {
+func NewModuleController(router chi.Router, live *registryconfig.Reloader, backend backend.Backend, storage storage.RegistryProviderStorage, recorder *downloads.Recorder, limits *ratelimit.Limiter) RegistryModuleController {
 	mc := &ModuleController{
-		Config:  config,
-		Backend: backend,
-		Storage: storage,
+		Config:    live.Current(),
+		Backend:   backend,
+		Storage:   storage,
+		Downloads: recorder,
 	}
 
 	router.Route("/terraform/modules/v1", func(r chi.Router) {
-		if !config.AllowAnonymousAccess {
-			handler := auth.NewAuthenticationMiddleware(config)
-			r.Use(handler.AuthenticationHandlerMiddleware)
-		}
+		access := NewNamespaceAccess(live, backend)
 
-		r.Get("/{ns}/{name}/{system}/versions", mc.Versions)
-		r.Get("/{ns}/{name}/{system}/{version}/download", mc.ModuleDownload)
+		r.With(access.Middleware, limits.Middleware(ratelimit.Reads)).Get("/{ns}/{name}/{system}/versions", mc.Versions)
+		r.With(access.Middleware, limits.Middleware(ratelimit.Reads)).Get("/{ns}/{name}/{system}/{version}/download", mc.ModuleDownload)
 	})
 
 	return mc
@@ -53,7 +53,7 @@ func (m *ModuleController) ModuleDownload(w http.ResponseWriter, r *http.Request
 
 	path, err := m.Backend.GetModuleDownload(r.Context(), params)
 	if err != nil {
-		log.Printf(err.Error())
+		slog.ErrorContext(r.Context(), "Error getting module download", "namespace", params.Namespace, "name", params.Name, "system", params.System, "version", params.Version, "error", err)
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -72,8 +72,21 @@ func (m *ModuleController) ModuleDownload(w http.ResponseWriter, r *http.Request
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 			Error: "Error generating download url.",
 		})
+		return
 	}
 
+	m.Downloads.Record(registrytypes.Download{
+		Kind:             registrytypes.DownloadKindModule,
+		Namespace:        params.Namespace,
+		Name:             params.Name,
+		Provider:         params.System,
+		Version:          params.Version,
+		TerraformVersion: downloads.TerraformVersion(r.UserAgent()),
+	})
+
+	// No ETag, the signed URL differs on every request and a revalidated copy
+	// would hand out an expired one
+	response.CacheControl(w, m.Config.CacheControl.Downloads)
 	w.Header().Set("X-Terraform-Get", uri)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -87,7 +100,7 @@ func (m *ModuleController) Versions(w http.ResponseWriter, r *http.Request) {
 
 	module, err := m.Backend.GetModuleVersions(r.Context(), params)
 	if err != nil {
-		log.Printf(err.Error())
+		slog.ErrorContext(r.Context(), "Error getting module versions", "namespace", params.Namespace, "name", params.Name, "system", params.System, "error", err)
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -101,5 +114,5 @@ func (m *ModuleController) Versions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JsonResponse(w, http.StatusOK, module)
+	response.ConditionalJsonResponse(w, r, m.Config.CacheControl.Versions, module)
 }
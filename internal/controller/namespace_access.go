@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/auth"
+	"go-terraform-registry/internal/backend"
+	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+)
+
+// NamespaceAccess guards the protocol endpoints. Requests without credentials
+// are only served when the namespace, module or provider has been made public,
+// and tokens scoped to other organizations need a share grant.
+type NamespaceAccess struct {
+	Config         *registryconfig.Reloader
+	Backend        backend.Backend
+	Authentication auth.AuthenticationMiddleware
+}
+
+func NewNamespaceAccess(live *registryconfig.Reloader, backend backend.Backend) *NamespaceAccess {
+	return &NamespaceAccess{
+		Config:         live,
+		Backend:        backend,
+		Authentication: auth.NewAuthenticationMiddleware(live),
+	}
+}
+
+func (n *NamespaceAccess) Middleware(next http.Handler) http.Handler {
+	authenticated := n.Authentication.AuthenticationHandlerMiddleware(n.authorize(next))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.Config.Current().AllowAnonymousAccess {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") == "" && n.isPublic(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+func (n *NamespaceAccess) isPublic(r *http.Request) bool {
+	namespace := chi.URLParam(r, "ns")
+	name := chi.URLParam(r, "name")
+	system := chi.URLParam(r, "system")
+
+	parameters := registrytypes.APIParameters{
+		Organization: namespace,
+		Namespace:    namespace,
+	}
+
+	resp, err := n.Backend.NamespacesGet(r.Context(), parameters)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Unable to read namespace visibility", "namespace", namespace, "error", err)
+		return false
+	}
+
+	attributes := resp.Data.Attributes
+	if attributes.Public {
+		return true
+	}
+
+	if system != "" {
+		return containsIgnoreCase(attributes.PublicModules, name+"/"+system)
+	}
+
+	return containsIgnoreCase(attributes.PublicProviders, name)
+}
+
+func (n *NamespaceAccess) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Tokens without an organization claim predate organization scoping
+		organizations, ok := r.Context().Value("organization").([]string)
+		if !ok || len(organizations) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		namespace := chi.URLParam(r, "ns")
+		if containsIgnoreCase(organizations, namespace) || n.isPublic(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		parameters := registrytypes.ShareParameters{
+			Organization: namespace,
+			ResourceType: "registry-providers",
+			Namespace:    namespace,
+			Name:         chi.URLParam(r, "name"),
+			Grantees:     organizations,
+		}
+		if system := chi.URLParam(r, "system"); system != "" {
+			parameters.ResourceType = "registry-modules"
+			parameters.Provider = system
+		}
+
+		granted, err := n.Backend.SharesGranted(r.Context(), parameters)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Unable to read shares", "namespace", namespace, "error", err)
+		}
+		if !granted {
+			response.JsonResponse(w, http.StatusForbidden, response.ErrorResponse{
+				Error: "Token is not permitted to read from namespace",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
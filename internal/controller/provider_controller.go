@@ -3,20 +3,22 @@ package controller
 import (
 	"fmt"
 	"github.com/go-chi/chi/v5"
-	"go-terraform-registry/internal/auth"
 	"go-terraform-registry/internal/backend"
 	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/downloads"
+	"go-terraform-registry/internal/ratelimit"
 	"go-terraform-registry/internal/response"
 	"go-terraform-registry/internal/storage"
 	registrytypes "go-terraform-registry/internal/types"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type ProviderController struct {
-	Config  registryconfig.RegistryConfig
-	Backend backend.Backend
-	Storage storage.RegistryProviderStorage
+	Config    registryconfig.RegistryConfig
+	Backend   backend.Backend
+	Storage   storage.RegistryProviderStorage
+	Downloads *downloads.Recorder
 }
 
 type RegistryProviderController interface {
@@ -24,21 +26,19 @@ type RegistryProviderController interface {
 	Versions(http.ResponseWriter, *http.Request)
 }
 
-func NewProviderController(router chi.Router, config registryconfig.RegistryConfig, backend backend.Backend, storage storage.RegistryProviderStorage) RegistryProviderController {
+func NewProviderController(router chi.Router, live *registryconfig.Reloader, backend backend.Backend, storage storage.RegistryProviderStorage, recorder *downloads.Recorder, limits *ratelimit.Limiter) RegistryProviderController {
 	pc := &ProviderController{
-		Config:  config,
-		Backend: backend,
-		Storage: storage,
+		Config:    live.Current(),
+		Backend:   backend,
+		Storage:   storage,
+		Downloads: recorder,
 	}
 
 	router.Route("/terraform/providers/v1", func(r chi.Router) {
-		if !config.AllowAnonymousAccess {
-			handler := auth.NewAuthenticationMiddleware(config)
-			r.Use(handler.AuthenticationHandlerMiddleware)
-		}
+		access := NewNamespaceAccess(live, backend)
 
-		r.Get("/{ns}/{name}/versions", pc.Versions)
-		r.Get("/{ns}/{name}/{version}/download/{os}/{arch}", pc.ProviderPackage)
+		r.With(access.Middleware, limits.Middleware(ratelimit.Reads)).Get("/{ns}/{name}/versions", pc.Versions)
+		r.With(access.Middleware, limits.Middleware(ratelimit.Reads)).Get("/{ns}/{name}/{version}/download/{os}/{arch}", pc.ProviderPackage)
 	})
 
 	return pc
@@ -59,7 +59,7 @@ func (p *ProviderController) ProviderPackage(w http.ResponseWriter, r *http.Requ
 
 	provider, err := p.Backend.GetProvider(r.Context(), params, userParams)
 	if err != nil {
-		log.Printf(err.Error())
+		slog.ErrorContext(r.Context(), "Error getting provider package", "namespace", params.Namespace, "name", params.Name, "version", params.Version, "os", params.OS, "arch", params.Architecture, "error", err)
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -86,6 +86,19 @@ func (p *ProviderController) ProviderPackage(w http.ResponseWriter, r *http.Requ
 	provider.ShasumsUrl = shaSumURL
 	provider.ShasumsSignatureUrl = shaSumSigURL
 
+	p.Downloads.Record(registrytypes.Download{
+		Kind:             registrytypes.DownloadKindProvider,
+		Namespace:        params.Namespace,
+		Name:             params.Name,
+		Version:          params.Version,
+		OS:               params.OS,
+		Architecture:     params.Architecture,
+		TerraformVersion: downloads.TerraformVersion(r.UserAgent()),
+	})
+
+	// No ETag, the signed URLs differ on every request and a revalidated copy
+	// would hand out expired ones
+	response.CacheControl(w, p.Config.CacheControl.Downloads)
 	response.JsonResponse(w, http.StatusOK, provider)
 }
 
@@ -101,7 +114,7 @@ func (p *ProviderController) Versions(w http.ResponseWriter, r *http.Request) {
 
 	provider, err := p.Backend.GetProviderVersions(r.Context(), params, userParams)
 	if err != nil {
-		log.Printf(err.Error())
+		slog.ErrorContext(r.Context(), "Error getting provider versions", "namespace", params.Namespace, "name", params.Name, "error", err)
 		response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 			Error: err.Error(),
 		})
@@ -115,5 +128,5 @@ func (p *ProviderController) Versions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.JsonResponse(w, http.StatusOK, provider)
+	response.ConditionalJsonResponse(w, r, p.Config.CacheControl.Versions, provider)
 }
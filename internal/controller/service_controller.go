@@ -2,19 +2,23 @@ package controller
 
 import (
 	"github.com/go-chi/chi/v5"
-	"log"
+	registryconfig "go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/response"
 	"net/http"
 )
 
 type ServiceController struct {
+	CacheControl string
 }
 
 type RegistryServiceController interface {
 	ServiceDiscovery(http.ResponseWriter, *http.Request)
 }
 
-func NewServiceController(r chi.Router) RegistryServiceController {
-	sc := &ServiceController{}
+func NewServiceController(r chi.Router, config registryconfig.RegistryConfig) RegistryServiceController {
+	sc := &ServiceController{
+		CacheControl: config.CacheControl.Discovery,
+	}
 
 	r.Get("/.well-known/terraform.json", sc.ServiceDiscovery)
 
@@ -26,6 +30,7 @@ func (s ServiceController) ServiceDiscovery(w http.ResponseWriter, r *http.Reque
 {
 	"providers.v1": "/terraform/providers/v1/",
 	"modules.v1": "/terraform/modules/v1/",
+	"tfe.v2": "/api/v2/",
 	"login.v1": {
 		"client": "terraform-cli",
 		"grant_types": [
@@ -37,9 +42,5 @@ func (s ServiceController) ServiceDiscovery(w http.ResponseWriter, r *http.Reque
 }
 `
 
-	w.Header().Set("Content-Type", "application/json")
-	_, err := w.Write([]byte(serviceData))
-	if err != nil {
-		log.Println("ERROR: ServiceDiscovery", err)
-	}
+	response.ConditionalResponse(w, r, s.CacheControl, "application/json", []byte(serviceData))
 }
@@ -0,0 +1,94 @@
+package downloads
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/metrics"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+const queueSize = 10000
+
+const (
+	// UnknownTerraformVersion is counted when the User-Agent is not Terraform's.
+	UnknownTerraformVersion = "unknown"
+	// OtherTerraformVersion is counted when the version is not a release, the
+	// User-Agent is client supplied and every version is a stored counter.
+	OtherTerraformVersion = "other"
+)
+
+var (
+	terraformUserAgent = regexp.MustCompile(`(?i)\bterraform/(\S*)`)
+	terraformVersion   = regexp.MustCompile(`^v?([0-9]{1,4}\.[0-9]{1,4}\.[0-9]{1,4}(?:-[0-9A-Za-z]{1,20})?)$`)
+)
+
+// Recorder counts downloads off the request path, a download is queued and
+// written to the backend by a background worker.
+type Recorder struct {
+	Backend backend.DownloadsBackend
+
+	queue chan registrytypes.Download
+}
+
+func NewRecorder(b backend.DownloadsBackend) *Recorder {
+	return &Recorder{
+		Backend: b,
+		queue:   make(chan registrytypes.Download, queueSize),
+	}
+}
+
+// Start writes queued downloads until ctx is done.
+func (rec *Recorder) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case download := <-rec.queue:
+				if err := rec.Backend.DownloadsRecord(ctx, download); err != nil {
+					slog.ErrorContext(ctx, "Error recording download", "kind", download.Kind, "namespace", download.Namespace, "name", download.Name, "version", download.Version, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Record queues a download without blocking, downloads are dropped while the
+// queue is full. It is a no-op on a nil recorder.
+func (rec *Recorder) Record(download registrytypes.Download) {
+	if rec == nil {
+		return
+	}
+
+	metrics.Download(download.Kind, download.Namespace)
+
+	if download.Timestamp.IsZero() {
+		download.Timestamp = time.Now().UTC()
+	}
+
+	select {
+	case rec.queue <- download:
+	default:
+		slog.Warn("Download queue full, dropping download", "kind", download.Kind, "namespace", download.Namespace, "name", download.Name, "version", download.Version)
+	}
+}
+
+// TerraformVersion extracts the version from a Terraform User-Agent such as
+// "Terraform/1.9.5 (+https://www.terraform.io)". Only major.minor.patch with
+// an optional short pre-release is kept, anything else is counted as other.
+func TerraformVersion(userAgent string) string {
+	match := terraformUserAgent.FindStringSubmatch(userAgent)
+	if match == nil {
+		return UnknownTerraformVersion
+	}
+
+	version := terraformVersion.FindStringSubmatch(match[1])
+	if version == nil {
+		return OtherTerraformVersion
+	}
+
+	return version[1]
+}
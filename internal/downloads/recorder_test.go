@@ -0,0 +1,35 @@
+package downloads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTerraformVersion(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Terraform/1.9.5 (+https://www.terraform.io)", "1.9.5"},
+		{"Terraform/v1.10.0-alpha20240606", "1.10.0-alpha20240606"},
+		{"terraform/1.5.7", "1.5.7"},
+		{"HashiCorp Terraform/1.6.0 (+https://www.terraform.io) terraform-provider-aws", "1.6.0"},
+		{"Terraform/1.9", "other"},
+		{"Terraform/1.9.5.1", "other"},
+		{"Terraform/1.9.5+build", "other"},
+		{"Terraform/1.9.5-" + strings.Repeat("a", 21), "other"},
+		{"Terraform/" + strings.Repeat("1", 100) + ".0.0", "other"},
+		{"Terraform/1.9.5-x.y", "other"},
+		{"Terraform/", "other"},
+		{"curl/8.5.0", "unknown"},
+		{"", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			if got := TerraformVersion(tt.userAgent); got != tt.want {
+				t.Errorf("TerraformVersion(%q) = %q, want %q", tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The created events are published when the version record is created. Its
+// artifacts are uploaded to storage afterwards, so the version may not be
+// downloadable yet.
+const (
+	ProviderVersionCreated            = "provider-version.created"
+	ProviderVersionDeleted            = "provider-version.deleted"
+	ProviderVersionVerificationFailed = "provider-version.verification-failed"
+	ModuleVersionCreated              = "module-version.created"
+	ModuleVersionDeleted              = "module-version.deleted"
+)
+
+// Types lists every event the registry publishes.
+var Types = []string{
+	ProviderVersionCreated,
+	ProviderVersionDeleted,
+	ProviderVersionVerificationFailed,
+	ModuleVersionCreated,
+	ModuleVersionDeleted,
+}
+
+type Event struct {
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Organization string            `json:"organization"`
+	Subject      string            `json:"subject"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+// Bus fans registry events out to in-process subscribers. Handlers run on the
+// publishing goroutine and must not block.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func NewEvent(eventType string, organization string, subject string, data map[string]string) Event {
+	return Event{
+		ID:           uuid.New().String(),
+		Type:         eventType,
+		Organization: organization,
+		Subject:      subject,
+		Timestamp:    time.Now().UTC(),
+		Data:         data,
+	}
+}
+
+func (b *Bus) Subscribe(handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish is a no-op on a nil bus so handlers work without one configured.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	slog.Info("Event", "id", event.ID, "type", event.Type, "organization", event.Organization, "subject", event.Subject)
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
+
+func ValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches reports whether an event type passes a subscription filter. An
+// empty filter matches everything, "provider-version.*" matches a prefix.
+func Matches(filter []string, eventType string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, f := range filter {
+		if f == "*" || f == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(f, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,119 @@
+package eventstream
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/events"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	queueSize    = 1000
+	clientBuffer = 64
+)
+
+// Broker appends registry events to the event log of the backend and fans
+// them out to the streams connected for their organization. Events are logged
+// before they are sent, so a client resuming from the log never misses one.
+type Broker struct {
+	Backend backend.EventsBackend
+
+	queue chan events.Event
+
+	mu      sync.Mutex
+	clients map[chan events.Event]string
+	closed  bool
+}
+
+func NewBroker(b backend.EventsBackend) *Broker {
+	return &Broker{
+		Backend: b,
+		queue:   make(chan events.Event, queueSize),
+		clients: map[chan events.Event]string{},
+	}
+}
+
+// Start logs and forwards queued events until ctx is done.
+func (s *Broker) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-s.queue:
+				if err := s.Backend.EventsCreate(ctx, event); err != nil {
+					slog.ErrorContext(ctx, "Error logging event", "id", event.ID, "error", err)
+				}
+				s.broadcast(event)
+			}
+		}
+	}()
+}
+
+// Handle queues an event. It is meant to be subscribed to the event bus and
+// never blocks the publisher.
+func (s *Broker) Handle(event events.Event) {
+	select {
+	case s.queue <- event:
+	default:
+		slog.Warn("Event stream queue full, dropping event", "id", event.ID, "type", event.Type)
+	}
+}
+
+// Subscribe returns a channel receiving the events of organization and a
+// function to stop receiving them. The channel is closed when the client falls
+// behind, it is expected to reconnect and resume from the event log.
+func (s *Broker) Subscribe(organization string) (<-chan events.Event, func()) {
+	ch := make(chan events.Event, clientBuffer)
+
+	s.mu.Lock()
+	if s.closed {
+		close(ch)
+	} else {
+		s.clients[ch] = organization
+	}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.clients[ch]; ok {
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close ends every stream and every stream subscribing afterwards, so the
+// server can shut down without waiting for clients to disconnect.
+func (s *Broker) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for ch := range s.clients {
+		delete(s.clients, ch)
+		close(ch)
+	}
+}
+
+func (s *Broker) broadcast(event events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch, organization := range s.clients {
+		if !strings.EqualFold(organization, event.Organization) {
+			continue
+		}
+
+		select {
+		case ch <- event:
+		default:
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
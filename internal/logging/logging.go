@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go-terraform-registry/internal/config"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in both directions, an ID sent by a
+// proxy in front of the registry is kept.
+const RequestIDHeader = "X-Request-Id"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// level is shared by every handler so it can change on reload.
+var level = new(slog.LevelVar)
+
+// Configure installs the default slog logger from the configuration. Records
+// logged with a request context carry its request ID.
+func Configure(c config.RegistryConfig) *slog.Logger {
+	l, err := parseLevel(c.LogLevel)
+	invalidLevel := err != nil
+	level.Set(l)
+
+	options := &slog.HandlerOptions{
+		Level: level,
+	}
+
+	var handler slog.Handler
+	switch strings.ToLower(c.LogFormat) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	default:
+		handler = slog.NewTextHandler(os.Stdout, options)
+	}
+
+	logger := slog.New(&contextHandler{Handler: handler})
+	slog.SetDefault(logger)
+
+	if invalidLevel {
+		logger.Warn("Invalid LOG_LEVEL, using info", "level", c.LogLevel)
+	}
+
+	return logger
+}
+
+// Reload is a config.ReloadFunc applying the log level.
+func Reload(c config.RegistryConfig) (func(), error) {
+	l, err := parseLevel(c.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		level.Set(l)
+	}, nil
+}
+
+// parseLevel returns the level named name, info when it is empty or invalid.
+func parseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+
+	return l, nil
+}
+
+// Fatal logs msg at error level and exits.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// RequestID returns the request ID of ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDMiddleware assigns every request an ID, returned in the
+// X-Request-Id response header and attached to the request context.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// RequestLogger logs every request once it completes, server errors at error
+// level.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.Log(r.Context(), level, "Request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", ww.BytesWritten(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contextHandler adds the request ID of the record context to the record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,109 @@
+package maintenance
+
+import (
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/metrics"
+	"go-terraform-registry/internal/response"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const defaultMessage = "The registry is in maintenance, changes are not accepted"
+
+// State is whether the registry is read-only, and the message changes are
+// refused with.
+type State struct {
+	Enabled bool
+	Message string
+}
+
+// Mode holds the maintenance state. It is set by the configuration and can be
+// switched at runtime from the admin endpoints, a reload only overrides that
+// when the maintenance configuration itself changed.
+type Mode struct {
+	mu         sync.RWMutex
+	state      State
+	configured config.MaintenanceConfig
+}
+
+func New(c config.RegistryConfig) *Mode {
+	m := &Mode{
+		configured: c.Maintenance,
+	}
+	m.Set(c.Maintenance.Enabled, c.Maintenance.Message)
+
+	return m
+}
+
+// State returns the maintenance state in effect.
+func (m *Mode) State() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.state
+}
+
+// Set enables or disables maintenance mode, an empty message falls back to a
+// default one.
+func (m *Mode) Set(enabled bool, message string) {
+	if message == "" {
+		message = defaultMessage
+	}
+
+	m.mu.Lock()
+	changed := m.state.Enabled != enabled
+	m.state = State{Enabled: enabled, Message: message}
+	m.mu.Unlock()
+
+	if enabled {
+		metrics.MaintenanceMode.Set(1)
+	} else {
+		metrics.MaintenanceMode.Set(0)
+	}
+
+	if changed && enabled {
+		slog.Warn("Maintenance mode enabled, changes are refused", "message", message)
+	} else if changed {
+		slog.Info("Maintenance mode disabled")
+	}
+}
+
+// Reload applies the maintenance configuration when it changed.
+func (m *Mode) Reload(c config.RegistryConfig) (func(), error) {
+	return func() {
+		m.mu.Lock()
+		changed := m.configured != c.Maintenance
+		m.configured = c.Maintenance
+		m.mu.Unlock()
+
+		if changed {
+			m.Set(c.Maintenance.Enabled, c.Maintenance.Message)
+		}
+	}, nil
+}
+
+// Middleware refuses requests that are not safe under /api and /asset while
+// in maintenance, the protocol endpoints and downloads are left alone.
+func (m *Mode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := m.State()
+		if !state.Enabled || safeMethod(r.Method) || !mutable(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		response.JsonResponse(w, http.StatusServiceUnavailable, response.ErrorResponse{
+			Error: state.Message,
+		})
+	})
+}
+
+func mutable(path string) bool {
+	return strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/asset/")
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const namespace = "terraform_registry"
+
+var (
+	Registry = prometheus.NewRegistry()
+
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "code"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	BackendOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "backend_operation_duration_seconds",
+		Help:      "Backend operation latency by Backend method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	BackendOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "backend_operation_errors_total",
+		Help:      "Failed backend operations by Backend method.",
+	}, []string{"operation"})
+
+	StorageOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_operations_total",
+		Help:      "Storage operations, including upload and download URL generation, by method and outcome.",
+	}, []string{"operation", "outcome"})
+
+	StorageUploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_upload_bytes_total",
+		Help:      "Bytes uploaded through the local storage asset endpoint.",
+	})
+
+	Downloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "downloads_total",
+		Help:      "Provider and module downloads by kind and namespace.",
+	}, []string{"kind", "namespace"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected for exceeding a rate limit budget.",
+	}, []string{"budget"})
+
+	ReadCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "read_cache_lookups_total",
+		Help:      "Read cache lookups by Backend method and outcome.",
+	}, []string{"operation", "outcome"})
+
+	MaintenanceMode = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "maintenance_mode",
+		Help:      "Whether the registry is in read-only maintenance mode.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		BackendOperationDuration,
+		BackendOperationErrors,
+		StorageOperations,
+		StorageUploadBytes,
+		Downloads,
+		RateLimited,
+		ReadCacheLookups,
+		MaintenanceMode,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Middleware counts and times requests by their chi route pattern, so path
+// parameters do not end up in the labels. Requests matching no route are
+// labelled "unmatched".
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ObserveBackend is a backend.Observer recording operation latency and errors.
+func ObserveBackend(ctx context.Context, operation string) (context.Context, func(error)) {
+	start := time.Now()
+
+	return ctx, func(err error) {
+		BackendOperationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		if err != nil {
+			BackendOperationErrors.WithLabelValues(operation).Inc()
+		}
+	}
+}
+
+// ObserveStorage is a storage.Observer counting operations by outcome.
+func ObserveStorage(ctx context.Context, operation string) (context.Context, func(error)) {
+	return ctx, func(err error) {
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+		StorageOperations.WithLabelValues(operation, outcome).Inc()
+	}
+}
+
+// Download counts a download of kind in namespace.
+func Download(kind string, ns string) {
+	Downloads.WithLabelValues(kind, strings.ToLower(ns)).Inc()
+}
@@ -0,0 +1,11 @@
+package models
+
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+type MaintenanceResponse struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
@@ -3,15 +3,15 @@ package pgp
 import (
 	"bytes"
 	"fmt"
+	"go-terraform-registry/internal/logging"
 	"golang.org/x/crypto/openpgp"
-	"log"
 	"strings"
 )
 
 func GetKeyID(publicKey string) []string {
 	entityList, err := openpgp.ReadArmoredKeyRing(bytes.NewBufferString(publicKey))
 	if err != nil {
-		log.Fatal(err)
+		logging.Fatal("Error reading armored key ring", "error", err)
 	}
 
 	var keys []string
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"go-terraform-registry/internal/backend"
+	registrytypes "go-terraform-registry/internal/types"
+	"sync"
+	"time"
+)
+
+var _ backend.RateLimitsBackend = &memoryStore{}
+
+// memoryStore keeps the buckets of a single replica.
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]registrytypes.RateLimitBucket
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		buckets: map[string]registrytypes.RateLimitBucket{},
+	}
+}
+
+func (m *memoryStore) RateLimitsTake(_ context.Context, key string, limit registrytypes.RateLimit, now time.Time) (registrytypes.RateLimitDecision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket, decision := backend.TakeRateLimitToken(m.buckets[key], limit, now)
+	m.buckets[key] = bucket
+
+	return decision, nil
+}
+
+func (m *memoryStore) RateLimitsPrune(_ context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, bucket := range m.buckets {
+		if bucket.Updated.Before(before) {
+			delete(m.buckets, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/metrics"
+	"go-terraform-registry/internal/response"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Budgets are drawn from separately, a client exhausting one keeps the others.
+const (
+	Reads   = "reads"
+	Writes  = "writes"
+	Uploads = "uploads"
+)
+
+const (
+	pruneInterval = 10 * time.Minute
+	pruneAfter    = time.Hour
+)
+
+// Limiter enforces the configured budgets with token buckets per client. The
+// buckets are kept in memory, or in the backend when they have to hold across
+// replicas.
+type Limiter struct {
+	Store             backend.RateLimitsBackend
+	Limits            map[string]registrytypes.RateLimit
+	TrustForwardedFor bool
+	TrustedProxies    int
+}
+
+func NewLimiter(c config.RegistryConfig, b backend.RateLimitsBackend) (*Limiter, error) {
+	l := &Limiter{
+		Store:             newMemoryStore(),
+		Limits:            map[string]registrytypes.RateLimit{},
+		TrustForwardedFor: c.RateLimit.TrustForwardedFor,
+		TrustedProxies:    c.RateLimit.TrustedProxies,
+	}
+
+	if c.RateLimit.Store == "postgres" {
+		if b == nil {
+			return nil, fmt.Errorf("backend %s cannot store rate limits", c.Backend)
+		}
+		l.Store = b
+	}
+
+	budgets := map[string]config.RateLimitBudget{
+		Reads:   c.RateLimit.Reads,
+		Writes:  c.RateLimit.Writes,
+		Uploads: c.RateLimit.Uploads,
+	}
+	for name, budget := range budgets {
+		if budget.Rate <= 0 {
+			continue
+		}
+
+		burst := budget.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(budget.Rate))
+		}
+		l.Limits[name] = registrytypes.RateLimit{Rate: budget.Rate, Burst: burst}
+		slog.Info("Rate limiting", "budget", name, "rate", budget.Rate, "burst", burst, "store", c.RateLimit.Store)
+	}
+
+	return l, nil
+}
+
+// Start prunes idle buckets until ctx is done, a bucket idle for long enough
+// has refilled and is the same as a missing one.
+func (l *Limiter) Start(ctx context.Context) {
+	if len(l.Limits) == 0 {
+		return
+	}
+
+	idle := pruneAfter
+	for _, limit := range l.Limits {
+		idle = max(idle, time.Duration(float64(limit.Burst)/limit.Rate*float64(time.Second)))
+	}
+
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Store.RateLimitsPrune(ctx, time.Now().Add(-idle)); err != nil {
+					slog.WarnContext(ctx, "Error pruning rate limit buckets", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Middleware limits requests to budget. Writes and uploads only count
+// requests that are not safe. It belongs after authentication so clients are
+// told apart by login rather than address where possible.
+func (l *Limiter) Middleware(budget string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limit, ok := l.Limits[budget]
+		if !ok {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if budget != Reads && safeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := budget + ":" + l.client(r)
+			decision, err := l.Store.RateLimitsTake(r.Context(), key, limit, time.Now())
+			if err != nil {
+				// Failing open, an unavailable store must not take the registry down
+				slog.WarnContext(r.Context(), "Error taking rate limit token", "key", key, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !decision.Allowed {
+				metrics.RateLimited.WithLabelValues(budget).Inc()
+
+				seconds := max(int(math.Ceil(decision.RetryAfter.Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				response.JsonResponse(w, http.StatusTooManyRequests, response.ErrorResponse{
+					Error: fmt.Sprintf("rate limit exceeded, retry in %d seconds", seconds),
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// client identifies the client of r by login when authenticated, and by IP
+// address otherwise.
+func (l *Limiter) client(r *http.Request) string {
+	if login, ok := r.Context().Value("login").(string); ok && login != "" {
+		return "login:" + strings.ToLower(login)
+	}
+
+	if l.TrustForwardedFor {
+		if ip := l.forwardedFor(r); ip != "" {
+			return "ip:" + ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// forwardedFor returns the X-Forwarded-For entry added by the outermost
+// trusted proxy. Every proxy appends the address it received the request
+// from, so only the last TrustedProxies entries can be relied on.
+func (l *Limiter) forwardedFor(r *http.Request) string {
+	var entries []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, entry := range strings.Split(header, ",") {
+			entries = append(entries, strings.TrimSpace(entry))
+		}
+	}
+
+	hops := max(l.TrustedProxies, 1)
+	if len(entries) < hops {
+		return ""
+	}
+
+	ip := net.ParseIP(entries[len(entries)-hops])
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
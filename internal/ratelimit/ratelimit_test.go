@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"go-terraform-registry/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpoofedForwardedFor(t *testing.T) {
+	c := config.RegistryConfig{
+		RateLimit: config.RateLimitConfig{
+			Store:             "memory",
+			TrustForwardedFor: true,
+			TrustedProxies:    1,
+			Reads:             config.RateLimitBudget{Rate: 0.001, Burst: 1},
+		},
+	}
+	limiter, err := NewLimiter(c, nil)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+
+	handler := limiter.Middleware(Reads)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// The client picks a new leftmost entry for every request, the proxy
+	// appends the address it saw
+	for i, spoofed := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/providers", nil)
+		req.RemoteAddr = "10.0.0.2:4000"
+		req.Header.Set("X-Forwarded-For", spoofed+", 203.0.113.7")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		want := http.StatusOK
+		if i > 0 {
+			want = http.StatusTooManyRequests
+		}
+		if recorder.Code != want {
+			t.Errorf("request %d with X-Forwarded-For %s: status = %d, want %d", i, spoofed, recorder.Code, want)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		trust     bool
+		proxies   int
+		forwarded []string
+		want      string
+	}{
+		{"untrusted", false, 1, []string{"203.0.113.7"}, "ip:10.0.0.2"},
+		{"one proxy", true, 1, []string{"198.51.100.1, 203.0.113.7"}, "ip:203.0.113.7"},
+		{"two proxies", true, 2, []string{"198.51.100.1, 203.0.113.7, 10.0.0.9"}, "ip:203.0.113.7"},
+		{"repeated headers", true, 2, []string{"198.51.100.1, 203.0.113.7", "10.0.0.9"}, "ip:203.0.113.7"},
+		{"fewer entries than proxies", true, 2, []string{"203.0.113.7"}, "ip:10.0.0.2"},
+		{"not an address", true, 1, []string{"unknown"}, "ip:10.0.0.2"},
+		{"no header", true, 1, nil, "ip:10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &Limiter{TrustForwardedFor: tt.trust, TrustedProxies: tt.proxies}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.2:4000"
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			if got := limiter.client(req); got != tt.want {
+				t.Errorf("client() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package readcache
+
+import (
+	"context"
+	apimodels "go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/models"
+	registrytypes "go-terraform-registry/internal/types"
+	"slices"
+	"strings"
+)
+
+var (
+	_ backend.RegistryBackend         = &cachedRegistryBackend{}
+	_ backend.ProviderVersionsBackend = &cachedProviderVersionsBackend{}
+	_ backend.ModuleVersionsBackend   = &cachedModuleVersionsBackend{}
+	_ backend.GPGKeysBackend          = &cachedGPGKeysBackend{}
+	_ backend.OrganizationsBackend    = &cachedOrganizationsBackend{}
+	_ backend.NamespacesBackend       = &cachedNamespacesBackend{}
+	_ backend.SharesBackend           = &cachedSharesBackend{}
+)
+
+// allScopes invalidates every entry. Changes to organizations, namespaces and
+// shares decide what any read returns, they are rare enough to drop it all.
+const allScopes = ""
+
+// Entries are scoped to the provider or module they were read from, a change
+// to any of its versions drops all of them regardless of the organization.
+// Invalidating a scope drops the scopes nested under it as well, namespaces
+// compare case-insensitively like in the backends.
+func providerScope(namespace string, name string) string {
+	return providerNamespaceScope(namespace) + "/" + name
+}
+
+func providerNamespaceScope(namespace string) string {
+	return "providers/" + strings.ToLower(namespace)
+}
+
+func moduleScope(namespace string, name string, system string) string {
+	return "modules/" + namespace + "/" + name + "/" + system
+}
+
+func cacheKey(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+type cachedRegistryBackend struct {
+	next  backend.RegistryBackend
+	cache *Cache
+}
+
+// GetProvider returns a deep copy of the cached response, callers fill in the
+// download URLs of their request. The other reads return the cached values
+// themselves, callers only encode them.
+func (c *cachedRegistryBackend) GetProvider(ctx context.Context, parameters registrytypes.ProviderPackageParameters, userParameters registrytypes.UserParameters) (*models.TerraformProviderPlatformResponse, error) {
+	key := cacheKey("GetProvider", userParameters.Organization, parameters.Namespace, parameters.Name, parameters.Version, parameters.OS, parameters.Architecture)
+	result, err := get(c.cache, "GetProvider", providerScope(parameters.Namespace, parameters.Name), key, func() (*models.TerraformProviderPlatformResponse, error) {
+		return c.next.GetProvider(ctx, parameters, userParameters)
+	})
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	copied := *result
+	copied.Protocols = slices.Clone(result.Protocols)
+	copied.SigningKeys.GPGPublicKeys = slices.Clone(result.SigningKeys.GPGPublicKeys)
+	return &copied, nil
+}
+
+func (c *cachedRegistryBackend) GetProviderVersions(ctx context.Context, parameters registrytypes.ProviderVersionParameters, userParameters registrytypes.UserParameters) (*models.TerraformAvailableProvider, error) {
+	key := cacheKey("GetProviderVersions", userParameters.Organization, parameters.Namespace, parameters.Name)
+	return get(c.cache, "GetProviderVersions", providerScope(parameters.Namespace, parameters.Name), key, func() (*models.TerraformAvailableProvider, error) {
+		return c.next.GetProviderVersions(ctx, parameters, userParameters)
+	})
+}
+
+func (c *cachedRegistryBackend) GetModuleVersions(ctx context.Context, parameters registrytypes.ModuleVersionParameters) (*models.TerraformAvailableModule, error) {
+	key := cacheKey("GetModuleVersions", parameters.Namespace, parameters.Name, parameters.System)
+	return get(c.cache, "GetModuleVersions", moduleScope(parameters.Namespace, parameters.Name, parameters.System), key, func() (*models.TerraformAvailableModule, error) {
+		return c.next.GetModuleVersions(ctx, parameters)
+	})
+}
+
+func (c *cachedRegistryBackend) GetModuleDownload(ctx context.Context, parameters registrytypes.ModuleDownloadParameters) (*string, error) {
+	return c.next.GetModuleDownload(ctx, parameters)
+}
+
+type cachedProviderVersionsBackend struct {
+	next  backend.ProviderVersionsBackend
+	cache *Cache
+}
+
+func (c *cachedProviderVersionsBackend) ProviderVersionsList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ProviderVersionsListResponse, error) {
+	return c.next.ProviderVersionsList(ctx, parameters)
+}
+
+func (c *cachedProviderVersionsBackend) ProviderVersionsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ProviderVersionsRequest) (*apimodels.ProviderVersionsResponse, error) {
+	defer c.cache.Invalidate(ctx, providerScope(parameters.Namespace, parameters.Name))
+	return c.next.ProviderVersionsCreate(ctx, parameters, request)
+}
+
+func (c *cachedProviderVersionsBackend) ProviderVersionsGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.ProviderVersionsResponse, error) {
+	return c.next.ProviderVersionsGet(ctx, parameters)
+}
+
+func (c *cachedProviderVersionsBackend) ProviderVersionsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	defer c.cache.Invalidate(ctx, providerScope(parameters.Namespace, parameters.Name))
+	return c.next.ProviderVersionsDelete(ctx, parameters)
+}
+
+// ProviderVersionPlatformsCreate invalidates as well, the platforms are part
+// of the cached provider reads.
+func (c *cachedProviderVersionsBackend) ProviderVersionPlatformsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ProviderVersionPlatformsRequest) (*apimodels.ProviderVersionPlatformsResponse, error) {
+	defer c.cache.Invalidate(ctx, providerScope(parameters.Namespace, parameters.Name))
+	return c.next.ProviderVersionPlatformsCreate(ctx, parameters, request)
+}
+
+type cachedModuleVersionsBackend struct {
+	next  backend.ModuleVersionsBackend
+	cache *Cache
+}
+
+func (c *cachedModuleVersionsBackend) ModuleVersionsCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.ModuleVersionsRequest) (*apimodels.ModuleVersionsResponse, error) {
+	defer c.cache.Invalidate(ctx, moduleScope(parameters.Namespace, parameters.Name, parameters.Provider))
+	return c.next.ModuleVersionsCreate(ctx, parameters, request)
+}
+
+func (c *cachedModuleVersionsBackend) ModuleVersionsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	defer c.cache.Invalidate(ctx, moduleScope(parameters.Namespace, parameters.Name, parameters.Provider))
+	return c.next.ModuleVersionsDelete(ctx, parameters)
+}
+
+type cachedGPGKeysBackend struct {
+	next  backend.GPGKeysBackend
+	cache *Cache
+}
+
+func (c *cachedGPGKeysBackend) GPGKeysList(ctx context.Context, namespaceFilter string, pageNumber *int, pageSize *int) (*apimodels.GPGKeysListResponse, error) {
+	return c.next.GPGKeysList(ctx, namespaceFilter, pageNumber, pageSize)
+}
+
+// GPGKeysAdd drops the reads of every provider in the namespace, they list
+// its signing keys.
+func (c *cachedGPGKeysBackend) GPGKeysAdd(ctx context.Context, request apimodels.GPGKeysRequest) (*apimodels.GPGKeysResponse, error) {
+	result, err := c.next.GPGKeysAdd(ctx, request)
+	if err == nil {
+		c.cache.Invalidate(ctx, providerNamespaceScope(request.Data.Attributes.Namespace))
+	}
+	return result, err
+}
+
+type cachedOrganizationsBackend struct {
+	next  backend.OrganizationsBackend
+	cache *Cache
+}
+
+func (c *cachedOrganizationsBackend) OrganizationsList(ctx context.Context) (*apimodels.OrganizationsListResponse, error) {
+	return c.next.OrganizationsList(ctx)
+}
+
+func (c *cachedOrganizationsBackend) OrganizationsCreate(ctx context.Context, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error) {
+	return c.next.OrganizationsCreate(ctx, request)
+}
+
+func (c *cachedOrganizationsBackend) OrganizationsGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.OrganizationsResponse, error) {
+	return c.next.OrganizationsGet(ctx, parameters)
+}
+
+func (c *cachedOrganizationsBackend) OrganizationsUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.OrganizationsRequest) (*apimodels.OrganizationsResponse, error) {
+	result, err := c.next.OrganizationsUpdate(ctx, parameters, request)
+	if err == nil {
+		c.cache.Invalidate(ctx, allScopes)
+	}
+	return result, err
+}
+
+func (c *cachedOrganizationsBackend) OrganizationsDelete(ctx context.Context, parameters registrytypes.APIParameters) (int, error) {
+	result, err := c.next.OrganizationsDelete(ctx, parameters)
+	if err == nil {
+		c.cache.Invalidate(ctx, allScopes)
+	}
+	return result, err
+}
+
+type cachedNamespacesBackend struct {
+	next  backend.NamespacesBackend
+	cache *Cache
+}
+
+func (c *cachedNamespacesBackend) NamespacesGet(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.NamespacesResponse, error) {
+	return c.next.NamespacesGet(ctx, parameters)
+}
+
+func (c *cachedNamespacesBackend) NamespacesUpdate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.NamespacesRequest) (*apimodels.NamespacesResponse, error) {
+	result, err := c.next.NamespacesUpdate(ctx, parameters, request)
+	if err == nil {
+		c.cache.Invalidate(ctx, allScopes)
+	}
+	return result, err
+}
+
+type cachedSharesBackend struct {
+	next  backend.SharesBackend
+	cache *Cache
+}
+
+func (c *cachedSharesBackend) SharesList(ctx context.Context, parameters registrytypes.APIParameters) (*apimodels.SharesListResponse, error) {
+	return c.next.SharesList(ctx, parameters)
+}
+
+func (c *cachedSharesBackend) SharesCreate(ctx context.Context, parameters registrytypes.APIParameters, request apimodels.SharesRequest) (*apimodels.SharesResponse, error) {
+	result, err := c.next.SharesCreate(ctx, parameters, request)
+	if err == nil {
+		c.cache.Invalidate(ctx, allScopes)
+	}
+	return result, err
+}
+
+func (c *cachedSharesBackend) SharesDelete(ctx context.Context, parameters registrytypes.APIParameters, shareID string) (int, error) {
+	result, err := c.next.SharesDelete(ctx, parameters, shareID)
+	if err == nil {
+		c.cache.Invalidate(ctx, allScopes)
+	}
+	return result, err
+}
+
+func (c *cachedSharesBackend) SharesGranted(ctx context.Context, parameters registrytypes.ShareParameters) (bool, error) {
+	return c.next.SharesGranted(ctx, parameters)
+}
@@ -0,0 +1,101 @@
+package readcache
+
+import (
+	"context"
+	apimodels "go-terraform-registry/internal/api/models"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/models"
+	registrytypes "go-terraform-registry/internal/types"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBackend serves the signing keys added to each namespace. The methods
+// the tests do not call are left to the nil embedded interfaces.
+type fakeBackend struct {
+	backend.RegistryBackend
+	backend.GPGKeysBackend
+
+	keys map[string][]string
+}
+
+func (f *fakeBackend) GetProvider(_ context.Context, parameters registrytypes.ProviderPackageParameters, _ registrytypes.UserParameters) (*models.TerraformProviderPlatformResponse, error) {
+	response := &models.TerraformProviderPlatformResponse{}
+	for _, keyID := range f.keys[strings.ToLower(parameters.Namespace)] {
+		response.SigningKeys.GPGPublicKeys = append(response.SigningKeys.GPGPublicKeys, models.GPGPublicKeys{KeyId: keyID})
+	}
+	return response, nil
+}
+
+func (f *fakeBackend) GPGKeysAdd(_ context.Context, request apimodels.GPGKeysRequest) (*apimodels.GPGKeysResponse, error) {
+	namespace := strings.ToLower(request.Data.Attributes.Namespace)
+	f.keys[namespace] = append(f.keys[namespace], request.Data.Attributes.AsciiArmor)
+	return &apimodels.GPGKeysResponse{}, nil
+}
+
+type fakeInvalidation struct {
+	backend.CacheInvalidationBackend
+
+	published []string
+}
+
+func (f *fakeInvalidation) CacheInvalidationPublish(_ context.Context, scope string) error {
+	f.published = append(f.published, scope)
+	return nil
+}
+
+func keyIDs(t *testing.T, b *backend.Backend, namespace string, name string) []string {
+	t.Helper()
+
+	response, err := b.GetProvider(context.Background(), registrytypes.ProviderPackageParameters{Namespace: namespace, Name: name}, registrytypes.UserParameters{})
+	if err != nil {
+		t.Fatalf("GetProvider() error = %v", err)
+	}
+
+	var ids []string
+	for _, key := range response.SigningKeys.GPGPublicKeys {
+		ids = append(ids, key.KeyId)
+	}
+	return ids
+}
+
+func TestGPGKeysAddInvalidatesNamespace(t *testing.T) {
+	fake := &fakeBackend{keys: map[string][]string{"hashicorp": {"old"}, "other": {"other"}}}
+	invalidation := &fakeInvalidation{}
+
+	c := New(config.RegistryConfig{ReadCache: config.ReadCacheConfig{TTL: time.Hour, MaxEntries: 100}}, invalidation)
+	b := c.Wrap(&backend.Backend{RegistryBackend: fake, GPGKeysBackend: fake})
+
+	// Fill the cache for two providers of the namespace and one elsewhere
+	for _, name := range []string{"aws", "google"} {
+		if got := keyIDs(t, b, "hashicorp", name); !slices.Equal(got, []string{"old"}) {
+			t.Fatalf("%s signing keys = %v, want [old]", name, got)
+		}
+	}
+	keyIDs(t, b, "other", "aws")
+
+	request := apimodels.GPGKeysRequest{}
+	request.Data.Attributes.Namespace = "HashiCorp"
+	request.Data.Attributes.AsciiArmor = "new"
+	if _, err := b.GPGKeysAdd(context.Background(), request); err != nil {
+		t.Fatalf("GPGKeysAdd() error = %v", err)
+	}
+
+	for _, name := range []string{"aws", "google"} {
+		if got := keyIDs(t, b, "hashicorp", name); !slices.Equal(got, []string{"old", "new"}) {
+			t.Errorf("%s signing keys after adding a key = %v, want [old new]", name, got)
+		}
+	}
+	if !slices.Equal(invalidation.published, []string{"providers/hashicorp"}) {
+		t.Errorf("published invalidations = %v, want [providers/hashicorp]", invalidation.published)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[cacheKey("GetProvider", "", "other", "aws", "", "", "")]; !ok {
+		t.Error("adding a key dropped the reads of another namespace")
+	}
+}
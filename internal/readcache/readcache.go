@@ -0,0 +1,189 @@
+package readcache
+
+import (
+	"container/list"
+	"context"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/metrics"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache keeps the results of the registry protocol reads for a while, so
+// repeated terraform init runs do not all reach the backend. Entries are
+// dropped when the versions they were read from change, on every replica when
+// the backend can tell them.
+type Cache struct {
+	TTL          time.Duration
+	MaxEntries   int
+	Invalidation backend.CacheInvalidationBackend
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	recent     *list.List
+	generation uint64
+}
+
+type entry struct {
+	key     string
+	scope   string
+	value   any
+	expires time.Time
+}
+
+func New(c config.RegistryConfig, invalidation backend.CacheInvalidationBackend) *Cache {
+	return &Cache{
+		TTL:          c.ReadCache.TTL,
+		MaxEntries:   c.ReadCache.MaxEntries,
+		Invalidation: invalidation,
+		entries:      map[string]*list.Element{},
+		recent:       list.New(),
+	}
+}
+
+// Wrap returns b with its registry reads cached and the changes they depend on
+// invalidating them, b is returned as is when the cache is disabled.
+func (c *Cache) Wrap(b *backend.Backend) *backend.Backend {
+	if c.TTL <= 0 {
+		return b
+	}
+
+	slog.Info("Caching registry reads", "ttl", c.TTL.String(), "max_entries", c.MaxEntries, "shared_invalidation", c.Invalidation != nil)
+
+	cached := *b
+	cached.RegistryBackend = &cachedRegistryBackend{next: b.RegistryBackend, cache: c}
+	cached.ProviderVersionsBackend = &cachedProviderVersionsBackend{next: b.ProviderVersionsBackend, cache: c}
+	cached.ModuleVersionsBackend = &cachedModuleVersionsBackend{next: b.ModuleVersionsBackend, cache: c}
+	cached.GPGKeysBackend = &cachedGPGKeysBackend{next: b.GPGKeysBackend, cache: c}
+	cached.OrganizationsBackend = &cachedOrganizationsBackend{next: b.OrganizationsBackend, cache: c}
+	cached.NamespacesBackend = &cachedNamespacesBackend{next: b.NamespacesBackend, cache: c}
+	cached.SharesBackend = &cachedSharesBackend{next: b.SharesBackend, cache: c}
+
+	return &cached
+}
+
+// Start listens for the invalidations published by the other replicas until
+// ctx is done.
+func (c *Cache) Start(ctx context.Context) {
+	if c.TTL <= 0 || c.Invalidation == nil {
+		return
+	}
+
+	go func() {
+		if err := c.Invalidation.CacheInvalidationListen(ctx, c.invalidate); err != nil {
+			slog.ErrorContext(ctx, "Error listening for read cache invalidations", "error", err)
+		}
+	}()
+}
+
+// Invalidate drops the entries under scope here and on the other replicas.
+// Failing to publish is only logged, the other replicas catch up once their
+// entries expire.
+func (c *Cache) Invalidate(ctx context.Context, scope string) {
+	c.invalidate(scope)
+
+	if c.Invalidation == nil {
+		return
+	}
+	if err := c.Invalidation.CacheInvalidationPublish(ctx, scope); err != nil {
+		slog.WarnContext(ctx, "Error publishing read cache invalidation", "scope", scope, "error", err)
+	}
+}
+
+// invalidate drops the entries under scope, including the scopes nested in
+// it, or every entry when scope is empty.
+func (c *Cache) invalidate(scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	if scope == "" {
+		clear(c.entries)
+		c.recent.Init()
+		return
+	}
+
+	for key, element := range c.entries {
+		if within(element.Value.(*entry).scope, scope) {
+			c.recent.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+}
+
+func within(scope string, parent string) bool {
+	return scope == parent || strings.HasPrefix(scope, parent+"/")
+}
+
+// lookup returns the live entry for key, and the generation a value loaded on
+// a miss has to be stored with.
+func (c *Cache) lookup(key string, now time.Time) (any, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, c.generation
+	}
+
+	e := element.Value.(*entry)
+	if now.After(e.expires) {
+		c.recent.Remove(element)
+		delete(c.entries, key)
+		return nil, false, c.generation
+	}
+
+	c.recent.MoveToFront(element)
+	return e.value, true, c.generation
+}
+
+// store keeps value unless an invalidation happened since it was looked up,
+// it may have been loaded before the change then. The least recently used
+// entries are evicted beyond MaxEntries.
+func (c *Cache) store(key string, scope string, value any, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.recent.Remove(element)
+	}
+	c.entries[key] = c.recent.PushFront(&entry{
+		key:     key,
+		scope:   scope,
+		value:   value,
+		expires: now.Add(c.TTL),
+	})
+
+	for c.recent.Len() > c.MaxEntries {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// get returns the cached result of operation for key, loading and caching it
+// on a miss. Errors are not cached.
+func get[T any](c *Cache, operation string, scope string, key string, load func() (T, error)) (T, error) {
+	value, ok, generation := c.lookup(key, time.Now())
+	if ok {
+		metrics.ReadCacheLookups.WithLabelValues(operation, "hit").Inc()
+		return value.(T), nil
+	}
+	metrics.ReadCacheLookups.WithLabelValues(operation, "miss").Inc()
+
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+
+	c.store(key, scope, result, generation, time.Now())
+	return result, nil
+}
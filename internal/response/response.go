@@ -1,27 +1,100 @@
 package response
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"log"
+	"go-terraform-registry/internal/logging"
+	"log/slog"
 	"net/http"
+	"strings"
 )
 
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error     string `json:"error"`
+	RequestID string `json:"request-id,omitempty"`
 }
 
 type AccessTokenResponse struct {
-	Token string `json:"access_token"`
+	Token     string `json:"access_token"`
+	TokenType string `json:"token_type,omitempty"`
 }
 
+// JsonResponse writes response as JSON. Server errors carry the request ID so
+// it can be quoted when reporting them.
 func JsonResponse(w http.ResponseWriter, httpStatus int, response any) {
+	if errorResponse, ok := response.(ErrorResponse); ok && httpStatus >= http.StatusInternalServerError && errorResponse.RequestID == "" {
+		errorResponse.RequestID = w.Header().Get(logging.RequestIDHeader)
+		response = errorResponse
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(httpStatus)
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		log.Println(err)
+		slog.Error("Error encoding response", "error", err)
+	}
+}
+
+// ConditionalJsonResponse writes response as JSON with a strong ETag of its
+// content, or 304 Not Modified when the client already holds that content.
+// cacheControl is omitted when empty.
+func ConditionalJsonResponse(w http.ResponseWriter, r *http.Request, cacheControl string, response any) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error encoding response", "error", err)
+		JsonResponse(w, http.StatusInternalServerError, ErrorResponse{
+			Error: "Error encoding response",
+		})
+		return
+	}
+
+	ConditionalResponse(w, r, cacheControl, "application/json", append(body, '\n'))
+}
+
+// ConditionalResponse writes body like ConditionalJsonResponse.
+func ConditionalResponse(w http.ResponseWriter, r *http.Request, cacheControl string, contentType string, body []byte) {
+	if NotModified(w, r, cacheControl, ETag(body)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		slog.ErrorContext(r.Context(), "Error writing response", "error", err)
+	}
+}
+
+// NotModified sets the ETag and Cache-Control headers and answers 304 Not
+// Modified when the request matches etag, the caller has nothing left to write
+// then.
+func NotModified(w http.ResponseWriter, r *http.Request, cacheControl string, etag string) bool {
+	w.Header().Set("ETag", etag)
+	CacheControl(w, cacheControl)
+
+	for _, candidate := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
 	}
+
+	return false
+}
+
+// CacheControl sets the Cache-Control header unless cacheControl is empty.
+func CacheControl(w http.ResponseWriter, cacheControl string) {
+	if cacheControl != "" {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+}
+
+// ETag returns the strong entity tag of content.
+func ETag(content []byte) string {
+	sum := sha256.Sum256(content)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
 }
 
 func FileResponse(w http.ResponseWriter, r *http.Request, filePath string, fileName string) {
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/controller"
+	"go-terraform-registry/internal/maintenance"
+	"go-terraform-registry/internal/metrics"
+	"go-terraform-registry/internal/storage"
+	"net/http"
+	"time"
+)
+
+// adminTimeout bounds admin requests, none of them transfer much.
+const adminTimeout = 30 * time.Second
+
+// newAdminServer returns the server for the operational endpoints, kept on
+// their own listener.
+func newAdminServer(c config.RegistryConfig, b *backend.Backend, s storage.RegistryProviderStorage, mode *maintenance.Mode) *http.Server {
+	cr := chi.NewRouter()
+	cr.Handle("/metrics", metrics.Handler())
+	_ = controller.NewHealthController(cr, b.BackendLifecycle, s)
+	_ = controller.NewMaintenanceController(cr, c, mode, b.AuditBackend)
+
+	return &http.Server{
+		Addr:              c.AdminAddress,
+		Handler:           cr,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		ReadTimeout:       adminTimeout,
+		WriteTimeout:      adminTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
+}
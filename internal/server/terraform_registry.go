@@ -2,60 +2,243 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
+	apimodels "go-terraform-registry/internal/api/models"
 	"go-terraform-registry/internal/backend"
 	"go-terraform-registry/internal/config"
 	"go-terraform-registry/internal/config/selector"
 	"go-terraform-registry/internal/controller"
+	"go-terraform-registry/internal/downloads"
+	"go-terraform-registry/internal/events"
+	"go-terraform-registry/internal/eventstream"
+	"go-terraform-registry/internal/logging"
+	"go-terraform-registry/internal/maintenance"
+	"go-terraform-registry/internal/metrics"
+	"go-terraform-registry/internal/ratelimit"
+	"go-terraform-registry/internal/readcache"
 	"go-terraform-registry/internal/storage"
-	"log"
+	"go-terraform-registry/internal/tracing"
+	registrytypes "go-terraform-registry/internal/types"
+	"go-terraform-registry/internal/webhooks"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func StartServer(version string) {
 	ctx := context.Background()
 
-	log.SetOutput(os.Stdout)
-	log.Println(fmt.Sprintf("Version: %s", version))
+	// Stop serving on SIGINT and SIGTERM, in-flight requests are drained first
+	signals, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Get configuration and select backend
+	path := os.Getenv(config.FileEnv)
+	c, err := config.LoadRegistryConfig(path)
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
+	}
+	live := config.NewReloader(path, c)
+
+	logging.Configure(c)
+	live.OnReload(logging.Reload)
+	slog.Info("Starting registry", "version", version)
+
+	shutdownTracing, err := tracing.Configure(ctx, c, version)
+	if err != nil {
+		logging.Fatal("Error configuring tracing", "error", err)
+	}
+
+	// Maintenance mode is switched by the configuration or the admin endpoints
+	mode := maintenance.New(c)
+	live.OnReload(mode.Reload)
 
 	cr := chi.NewRouter()
-	cr.Use(middleware.Logger)
+	cr.Use(logging.RequestIDMiddleware)
+	cr.Use(logging.RequestLogger)
+	cr.Use(tracing.Middleware)
+	cr.Use(metrics.Middleware)
+	cr.Use(mode.Middleware)
 
-	// Get configuration and select backend
-	c := config.GetRegistryConfig()
 	b := selector.SelectBackend(ctx, c)
 
-	err := b.Configure(ctx)
+	err = b.Configure(ctx)
 	if err != nil {
-		log.Fatal(err)
+		logging.Fatal("Error configuring backend", "error", err)
 	}
-	defer func(b *backend.Backend, ctx context.Context) {
-		err := b.Close(ctx)
-		if err != nil {
-			log.Println(err)
-		}
-	}(b, ctx)
+	b = backend.Observe(backend.Observe(b, metrics.ObserveBackend), tracing.ObserveBackend)
+
+	err = ensureOrganization(ctx, b, c.Organization)
+	if err != nil {
+		logging.Fatal("Error creating default organization", "organization", c.Organization, "error", err)
+	}
+
+	// Background workers outlive the listeners so drained requests can still
+	// hand them work
+	workers, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	limits, err := ratelimit.NewLimiter(c, b.RateLimitsBackend)
+	if err != nil {
+		logging.Fatal("Error configuring rate limits", "error", err)
+	}
+	limits.Start(workers)
+
+	// Cache the protocol reads, backend metrics and traces only see the misses
+	cache := readcache.New(c, b.CacheInvalidationBackend)
+	b = cache.Wrap(b)
+	cache.Start(workers)
 
 	// Configure storage
-	s := selector.SelectStorage(ctx, c)
+	s := storage.Observe(storage.Observe(selector.SelectStorage(ctx, c), metrics.ObserveStorage), tracing.ObserveStorage)
 	if sae, ok := s.(storage.RegistryProviderStorageAssetEndpoint); ok {
-		sae.ConfigureEndpoint(ctx, cr)
+		cr.Group(func(r chi.Router) {
+			r.Use(limits.Middleware(ratelimit.Uploads))
+			sae.ConfigureEndpoint(ctx, r)
+		})
 	}
 
+	// Count downloads off the request path
+	recorder := downloads.NewRecorder(b.DownloadsBackend)
+	recorder.Start(workers)
+
 	// Configure controllers
-	_ = controller.NewServiceController(cr)
-	_ = controller.NewProviderController(cr, c, *b, s)
-	_ = controller.NewModuleController(cr, c, *b, s)
-	_ = controller.NewAuthenticationController(cr, c)
+	_ = controller.NewServiceController(cr, c)
+	_ = controller.NewPublicHealthController(cr, b.BackendLifecycle, s)
+	_ = controller.NewProviderController(cr, live, *b, s, recorder, limits)
+	_ = controller.NewModuleController(cr, live, *b, s, recorder, limits)
+	_ = controller.NewAuthenticationController(cr, live)
+	_ = controller.NewJWKSController(cr, live)
 
-	apiController := controller.NewAPIController(c, *b, s)
+	// Deliver registry events to webhooks and event streams
+	bus := events.NewBus()
+	dispatcher := webhooks.NewDispatcher(live, b.WebhooksBackend)
+	dispatcher.Start(workers)
+	bus.Subscribe(dispatcher.Handle)
+	broker := eventstream.NewBroker(b.EventsBackend)
+	broker.Start(workers)
+	bus.Subscribe(broker.Handle)
+
+	apiController := controller.NewAPIController(live, *b, s, bus, broker, limits)
 	apiController.CreateEndpoints(cr)
 
-	err = http.ListenAndServe(":8080", cr)
+	server := &http.Server{
+		Addr:              c.ListenAddress,
+		Handler:           cr,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		ReadTimeout:       c.ReadTimeout,
+		WriteTimeout:      c.WriteTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
+	// Event streams never finish on their own
+	server.RegisterOnShutdown(broker.Close)
+
+	if c.TLSCertFile != "" {
+		server.TLSConfig, err = newTLSConfig(c)
+		if err != nil {
+			logging.Fatal("Error configuring TLS", "error", err)
+		}
+	}
+
+	// Serve metrics apart from the registry so they are not exposed with it
+	admin := newAdminServer(c, b, s, mode)
+
+	// Reload the configuration on SIGHUP, requests in flight keep the settings
+	// they started with
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	go func() {
+		for range reload {
+			if err := live.Reload(); err != nil {
+				slog.Error("Error reloading configuration, keeping the current one", "error", err)
+			}
+		}
+	}()
+
+	errs := make(chan error, 2)
+	go func() {
+		if server.TLSConfig != nil {
+			slog.Info("Serving registry with TLS", "address", c.ListenAddress, "client_certificates", server.TLSConfig.ClientAuth.String())
+			errs <- server.ListenAndServeTLS(c.TLSCertFile, c.TLSKeyFile)
+		} else {
+			slog.Info("Serving registry", "address", c.ListenAddress)
+			errs <- server.ListenAndServe()
+		}
+	}()
+	go func() {
+		slog.Info("Serving admin endpoints", "address", c.AdminAddress)
+		errs <- admin.ListenAndServe()
+	}()
+
+	failed := false
+	select {
+	case <-signals.Done():
+		slog.Info("Shutting down, draining requests", "timeout", c.ShutdownTimeout.String())
+	case err := <-errs:
+		slog.Error("Error serving requests", "error", err)
+		failed = true
+	}
+	stop()
+
+	shutdown, cancel := context.WithTimeout(ctx, c.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdown); err != nil {
+		slog.Error("Error draining registry requests", "error", err)
+	}
+	if err := admin.Shutdown(shutdown); err != nil {
+		slog.Error("Error draining admin requests", "error", err)
+	}
+
+	stopWorkers()
+
+	if err := s.Close(shutdown); err != nil {
+		slog.Error("Error closing storage", "error", err)
+	}
+	if err := b.Close(shutdown); err != nil {
+		slog.Error("Error closing backend", "error", err)
+	}
+	if err := shutdownTracing(shutdown); err != nil {
+		slog.Error("Error shutting down tracing", "error", err)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+
+	slog.Info("Shutdown complete")
+}
+
+// ensureOrganization creates the default organization so existing tokens keep
+// working now that unknown organizations are rejected.
+func ensureOrganization(ctx context.Context, b *backend.Backend, name string) error {
+	parameters := registrytypes.APIParameters{
+		Organization: name,
+	}
+
+	organization, err := b.OrganizationsGet(ctx, parameters)
+	if err != nil || organization != nil {
+		return err
+	}
+
+	request := apimodels.OrganizationsRequest{
+		Data: apimodels.OrganizationsDataRequest{
+			Type: "organizations",
+			Attributes: apimodels.OrganizationsAttributesRequest{
+				Name: &name,
+			},
+		},
+	}
+
+	_, err = b.OrganizationsCreate(ctx, request)
 	if err != nil {
-		panic(err)
+		return err
 	}
+
+	slog.InfoContext(ctx, "Created default organization", "organization", name)
+
+	return nil
 }
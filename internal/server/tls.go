@@ -0,0 +1,48 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go-terraform-registry/internal/config"
+	"os"
+)
+
+func newTLSConfig(c config.RegistryConfig) (*tls.Config, error) {
+	if c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("TLS_KEY_FILE is required when TLS_CERT_FILE is set")
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	switch c.TLSClientAuth {
+	case "", "none":
+		tlsConfig.ClientAuth = tls.NoClientCert
+		return tlsConfig, nil
+	case "optional":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "required":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported TLS_CLIENT_AUTH: %s", c.TLSClientAuth)
+	}
+
+	if c.TLSClientCAFile == "" {
+		return nil, fmt.Errorf("TLS_CLIENT_CA_FILE is required for client certificate authentication")
+	}
+
+	data, err := os.ReadFile(c.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", c.TLSClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+
+	return tlsConfig, nil
+}
@@ -8,10 +8,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/metrics"
 	"go-terraform-registry/internal/response"
 	"go-terraform-registry/internal/storage"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path"
@@ -54,19 +55,15 @@ type AssetClaims struct {
 	jwt.RegisteredClaims
 }
 
-func (l *LocalStorage) ConfigureEndpoint(_ context.Context, cr *chi.Mux) {
+func (l *LocalStorage) ConfigureEndpoint(ctx context.Context, cr chi.Router) {
 	ae := &AssetEndpoint{
 		secretKey: l.secretKey,
 	}
-	l.Endpoint = os.Getenv("LOCAL_STORAGE_ASSETS_ENDPOINT")
-	if l.Endpoint == "" {
-		l.Endpoint = "http://localhost:8080"
-	}
-	ae.AssetPath = os.Getenv("LOCAL_STORAGE_ASSETS_PATH")
+	l.Endpoint = l.Config.LocalStorage.AssetsEndpoint
+	ae.AssetPath = l.Config.LocalStorage.AssetsPath
 	l.AssetPath = ae.AssetPath
 
-	log.Printf("Local Storage Endpoint: %s", l.Endpoint)
-	log.Printf("Local Storage Asset Path: %s", ae.AssetPath)
+	slog.InfoContext(ctx, "Local storage asset endpoint", "endpoint", l.Endpoint, "path", ae.AssetPath)
 
 	cr.Route("/asset", func(r chi.Router) {
 		r.Put("/upload/{token}", ae.UploadFile)
@@ -75,11 +72,11 @@ func (l *LocalStorage) ConfigureEndpoint(_ context.Context, cr *chi.Mux) {
 	})
 }
 
-func (l *LocalStorage) ConfigureStorage(_ context.Context) error {
+func (l *LocalStorage) ConfigureStorage(ctx context.Context) error {
 	secretKey, err := generateRandomSecret(32)
 	l.secretKey = []byte(secretKey)
 
-	log.Println("Using local storage for providers & endpoints.")
+	slog.InfoContext(ctx, "Using local storage for providers and modules")
 
 	return err
 }
@@ -158,10 +155,10 @@ func (a *AssetEndpoint) DownloadFile(w http.ResponseWriter, r *http.Request) {
 	fileName := path.Base(joinedPath)
 
 	if r.Method == http.MethodHead {
-		log.Printf("HEAD request for file: %s", joinedPath)
+		slog.DebugContext(r.Context(), "HEAD request for file", "path", joinedPath)
 		fileInfo, err := os.Stat(joinedPath)
 		if err != nil {
-			log.Printf("File not found: %s", joinedPath)
+			slog.WarnContext(r.Context(), "File not found", "path", joinedPath)
 			response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
 				Error: "file not found",
 			})
@@ -177,7 +174,7 @@ func (a *AssetEndpoint) DownloadFile(w http.ResponseWriter, r *http.Request) {
 
 	_, err = os.Stat(joinedPath)
 	if err != nil {
-		log.Printf("File not found: %s", joinedPath)
+		slog.WarnContext(r.Context(), "File not found", "path", joinedPath)
 		response.JsonResponse(w, http.StatusNotFound, response.ErrorResponse{
 			Error: "file not found",
 		})
@@ -187,8 +184,8 @@ func (a *AssetEndpoint) DownloadFile(w http.ResponseWriter, r *http.Request) {
 	response.FileResponse(w, r, joinedPath, fileName)
 }
 
-func (l *LocalStorage) RemoveFile(_ context.Context, path string) error {
-	log.Printf("Removing file: %s", path)
+func (l *LocalStorage) RemoveFile(ctx context.Context, path string) error {
+	slog.InfoContext(ctx, "Removing file", "path", path)
 
 	convertedPath := filepath.FromSlash(path)
 	joinedPath := filepath.Join(l.AssetPath, convertedPath)
@@ -202,7 +199,7 @@ func (l *LocalStorage) RemoveFile(_ context.Context, path string) error {
 }
 
 func (l *LocalStorage) RemoveDirectory(ctx context.Context, path string) error {
-	log.Printf("Removing directory: %s", path)
+	slog.InfoContext(ctx, "Removing directory", "path", path)
 
 	convertedPath := filepath.FromSlash(path)
 	joinedPath := filepath.Join(l.AssetPath, convertedPath)
@@ -254,6 +251,8 @@ func uploadFile(w http.ResponseWriter, r *http.Request, assetPath string, secret
 		})
 		return
 	}
+
+	metrics.StorageUploadBytes.Add(float64(len(fileData)))
 }
 
 func uploadFileChunk(w http.ResponseWriter, r *http.Request, assetPath string, secretKey []byte) {
@@ -327,8 +326,10 @@ func uploadFileChunk(w http.ResponseWriter, r *http.Request, assetPath string, s
 		return
 	}
 
+	metrics.StorageUploadBytes.Add(float64(len(chunkData)))
+
 	if chunkNumber == totalChunks {
-		err = assembleFile(directoryPath, fileName, totalChunks)
+		err = assembleFile(r.Context(), directoryPath, fileName, totalChunks)
 		if err != nil {
 			response.JsonResponse(w, http.StatusInternalServerError, response.ErrorResponse{
 				Error: "Failed to assemble file",
@@ -338,7 +339,7 @@ func uploadFileChunk(w http.ResponseWriter, r *http.Request, assetPath string, s
 	}
 }
 
-func assembleFile(directoryPath string, fileName string, totalChunks int) error {
+func assembleFile(ctx context.Context, directoryPath string, fileName string, totalChunks int) error {
 	fullPath := filepath.Join(directoryPath, fileName)
 	assembledFile, err := os.Create(fullPath)
 	if err != nil {
@@ -347,12 +348,12 @@ func assembleFile(directoryPath string, fileName string, totalChunks int) error
 	defer func(assembledFile *os.File) {
 		err := assembledFile.Close()
 		if err != nil {
-			fmt.Println("Error closing file:", err)
+			slog.ErrorContext(ctx, "Error closing file", "path", fullPath, "error", err)
 		}
 	}(assembledFile)
 
 	for i := 1; i <= totalChunks; i++ {
-		err := processChunk(assembledFile, fileName, directoryPath, i)
+		err := processChunk(ctx, assembledFile, fileName, directoryPath, i)
 		if err != nil {
 			return err
 		}
@@ -361,7 +362,7 @@ func assembleFile(directoryPath string, fileName string, totalChunks int) error
 	return nil
 }
 
-func processChunk(assembledFile *os.File, fileName string, directoryPath string, chunkNumber int) error {
+func processChunk(ctx context.Context, assembledFile *os.File, fileName string, directoryPath string, chunkNumber int) error {
 	chunkedFileName := fmt.Sprintf("%s.part%d", fileName, chunkNumber)
 	chunkedPath := filepath.Join(directoryPath, chunkedFileName)
 	chunkFile, err := os.Open(chunkedPath)
@@ -371,11 +372,11 @@ func processChunk(assembledFile *os.File, fileName string, directoryPath string,
 	defer func(chunkFile *os.File, path string) {
 		err := chunkFile.Close()
 		if err != nil {
-			fmt.Println("Error closing file:", err)
+			slog.ErrorContext(ctx, "Error closing file", "path", path, "error", err)
 		}
 		err = os.Remove(path)
 		if err != nil {
-			fmt.Println("Error removing file:", err)
+			slog.ErrorContext(ctx, "Error removing file", "path", path, "error", err)
 		}
 	}(chunkFile, chunkedPath)
 
@@ -387,6 +388,36 @@ func processChunk(assembledFile *os.File, fileName string, directoryPath string,
 	return nil
 }
 
+func (l *LocalStorage) Close(_ context.Context) error {
+	return nil
+}
+
+// HealthCheck creates and removes a file in the asset path to make sure it is
+// writable, the path is created like on upload.
+func (l *LocalStorage) HealthCheck(_ context.Context) error {
+	dir := l.AssetPath
+	if dir == "" {
+		dir = "."
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".healthcheck-*")
+	if err != nil {
+		return err
+	}
+
+	name := f.Name()
+	err = f.Close()
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
 func generateRandomSecret(n int) (string, error) {
 	bytes := make([]byte, n/2) // Each byte is 2 hex chars
 	_, err := rand.Read(bytes)
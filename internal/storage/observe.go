@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+)
+
+// Observer is called before every storage operation with the name of the
+// RegistryProviderStorage method. The returned context is passed on to the
+// operation and the returned function is called with its error once it
+// completes.
+type Observer func(ctx context.Context, operation string) (context.Context, func(error))
+
+// Observe returns a storage calling observer around every operation of s. The
+// asset endpoint of s, if any, is kept.
+func Observe(s RegistryProviderStorage, observer Observer) RegistryProviderStorage {
+	observed := &observedStorage{next: s, observe: observer}
+	if endpoint, ok := s.(RegistryProviderStorageAssetEndpoint); ok {
+		return &observedStorageWithEndpoint{observedStorage: observed, endpoint: endpoint}
+	}
+
+	return observed
+}
+
+type observedStorage struct {
+	next    RegistryProviderStorage
+	observe Observer
+}
+
+func (o *observedStorage) ConfigureStorage(ctx context.Context) error {
+	ctx, done := o.observe(ctx, "ConfigureStorage")
+	err := o.next.ConfigureStorage(ctx)
+	done(err)
+	return err
+}
+
+func (o *observedStorage) GenerateUploadURL(ctx context.Context, path string) (string, error) {
+	ctx, done := o.observe(ctx, "GenerateUploadURL")
+	result, err := o.next.GenerateUploadURL(ctx, path)
+	done(err)
+	return result, err
+}
+
+func (o *observedStorage) GenerateDownloadURL(ctx context.Context, path string) (string, error) {
+	ctx, done := o.observe(ctx, "GenerateDownloadURL")
+	result, err := o.next.GenerateDownloadURL(ctx, path)
+	done(err)
+	return result, err
+}
+
+func (o *observedStorage) RemoveFile(ctx context.Context, path string) error {
+	ctx, done := o.observe(ctx, "RemoveFile")
+	err := o.next.RemoveFile(ctx, path)
+	done(err)
+	return err
+}
+
+func (o *observedStorage) RemoveDirectory(ctx context.Context, path string) error {
+	ctx, done := o.observe(ctx, "RemoveDirectory")
+	err := o.next.RemoveDirectory(ctx, path)
+	done(err)
+	return err
+}
+
+func (o *observedStorage) HealthCheck(ctx context.Context) error {
+	ctx, done := o.observe(ctx, "HealthCheck")
+	err := o.next.HealthCheck(ctx)
+	done(err)
+	return err
+}
+
+func (o *observedStorage) Close(ctx context.Context) error {
+	return o.next.Close(ctx)
+}
+
+type observedStorageWithEndpoint struct {
+	*observedStorage
+	endpoint RegistryProviderStorageAssetEndpoint
+}
+
+func (o *observedStorageWithEndpoint) ConfigureEndpoint(ctx context.Context, cr chi.Router) {
+	o.endpoint.ConfigureEndpoint(ctx, cr)
+}
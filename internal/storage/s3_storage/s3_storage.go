@@ -9,7 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"go-terraform-registry/internal/config"
 	"go-terraform-registry/internal/storage"
-	"log"
+	"log/slog"
 	"time"
 )
 
@@ -33,22 +33,22 @@ func (s *S3Storage) ConfigureStorage(ctx context.Context) error {
 		return err
 	}
 
-	if s.Config.AssumeRoleARN != "" {
+	if s.Config.S3.AssumeRoleARN != "" {
 		stsClient := sts.NewFromConfig(cfg)
-		credentials := stscreds.NewAssumeRoleProvider(stsClient, s.Config.AssumeRoleARN)
+		credentials := stscreds.NewAssumeRoleProvider(stsClient, s.Config.S3.AssumeRoleARN)
 		cfg.Credentials = aws.NewCredentialsCache(credentials)
 	}
 
 	s.client = s3.NewFromConfig(cfg)
 
-	log.Println("Using S3 storage for providers & endpoints.")
+	slog.InfoContext(ctx, "Using S3 storage for providers and modules", "bucket", s.Config.S3.BucketName)
 
 	return nil
 }
 
 func (s *S3Storage) GenerateUploadURL(ctx context.Context, path string) (string, error) {
 	preSignClient := s3.NewPresignClient(s.client)
-	bucketName := s.Config.S3BucketName
+	bucketName := s.Config.S3.BucketName
 
 	preSignedGetObject, err := preSignClient.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket: &bucketName,
@@ -64,7 +64,7 @@ func (s *S3Storage) GenerateUploadURL(ctx context.Context, path string) (string,
 
 func (s *S3Storage) GenerateDownloadURL(ctx context.Context, path string) (string, error) {
 	preSignClient := s3.NewPresignClient(s.client)
-	bucketName := s.Config.S3BucketName
+	bucketName := s.Config.S3.BucketName
 
 	preSignedGetObject, err := preSignClient.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: &bucketName,
@@ -78,12 +78,23 @@ func (s *S3Storage) GenerateDownloadURL(ctx context.Context, path string) (strin
 	return preSignedGetObject.URL, nil
 }
 
-func (s *S3Storage) RemoveFile(_ context.Context, path string) error {
-	log.Printf("Removing file: %s", path)
+func (s *S3Storage) HealthCheck(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.Config.S3.BucketName),
+	})
+	return err
+}
+
+func (s *S3Storage) Close(_ context.Context) error {
+	return nil
+}
+
+func (s *S3Storage) RemoveFile(ctx context.Context, path string) error {
+	slog.InfoContext(ctx, "Removing file", "path", path)
 	return nil
 }
 
 func (s *S3Storage) RemoveDirectory(ctx context.Context, path string) error {
-	log.Printf("Removing directory: %s", path)
+	slog.InfoContext(ctx, "Removing directory", "path", path)
 	return nil
 }
@@ -11,8 +11,11 @@ type RegistryProviderStorage interface {
 	GenerateDownloadURL(ctx context.Context, path string) (string, error)
 	RemoveFile(ctx context.Context, path string) error
 	RemoveDirectory(ctx context.Context, path string) error
+	// HealthCheck returns an error when assets cannot be stored.
+	HealthCheck(ctx context.Context) error
+	Close(ctx context.Context) error
 }
 
 type RegistryProviderStorageAssetEndpoint interface {
-	ConfigureEndpoint(ctx context.Context, cr *chi.Mux)
+	ConfigureEndpoint(ctx context.Context, cr chi.Router)
 }
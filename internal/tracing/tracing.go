@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go-terraform-registry/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	serviceName = "go-terraform-registry"
+	tracerName  = "go-terraform-registry"
+)
+
+// Configure installs the global tracer provider exporting spans over OTLP/HTTP
+// to the configured collector, and the W3C trace context propagator. Without
+// a collector configured a no-op provider is installed and spans are not
+// recorded. The returned function flushes and stops the exporter.
+func Configure(ctx context.Context, c config.RegistryConfig, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if c.TracingEndpoint == "" {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(c.TracingEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("Exporting traces", "endpoint", c.TracingEndpoint)
+
+	return provider.Shutdown, nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Middleware starts a server span for every request, continuing the trace of
+// an incoming traceparent header. The span is named after the chi route
+// pattern once the request has been routed.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer().Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+			semconv.UserAgentOriginal(r.UserAgent()),
+		))
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(semconv.HTTPRoute(pattern))
+			}
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// ObserveBackend is a backend.Observer starting a span per operation.
+func ObserveBackend(ctx context.Context, operation string) (context.Context, func(error)) {
+	return observe(ctx, "backend."+operation, attribute.String("registry.backend.operation", operation))
+}
+
+// ObserveStorage is a storage.Observer starting a span per operation.
+func ObserveStorage(ctx context.Context, operation string) (context.Context, func(error)) {
+	return observe(ctx, "storage."+operation, attribute.String("registry.storage.operation", operation))
+}
+
+func observe(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attributes...))
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"go-terraform-registry/internal/config"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collector is an in-process OTLP/HTTP receiver keeping every exported span.
+type collector struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request := &collectortrace.ExportTraceServiceRequest{}
+	if err := proto.Unmarshal(body, request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	for _, resourceSpans := range request.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			c.spans = append(c.spans, scopeSpans.GetSpans()...)
+		}
+	}
+	c.mu.Unlock()
+
+	response, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(response)
+}
+
+func restoreGlobalProvider(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+}
+
+func attributes(span *tracepb.Span) map[string]any {
+	attrs := map[string]any{}
+	for _, kv := range span.GetAttributes() {
+		switch v := kv.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.GetKey()] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.GetKey()] = v.IntValue
+		}
+	}
+	return attrs
+}
+
+func TestMiddlewareExportsServerSpan(t *testing.T) {
+	restoreGlobalProvider(t)
+
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	shutdown, err := Configure(context.Background(), config.RegistryConfig{
+		TracingEndpoint:    server.URL + "/v1/traces",
+		TracingSampleRatio: 1,
+	}, "test")
+	if err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/v1/providers/{namespace}/{name}/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/providers/hashicorp/aws/versions", nil))
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.spans) != 1 {
+		t.Fatalf("collector received %d spans, want 1", len(c.spans))
+	}
+
+	span := c.spans[0]
+	if want := "GET /v1/providers/{namespace}/{name}/versions"; span.GetName() != want {
+		t.Errorf("span name = %q, want %q", span.GetName(), want)
+	}
+	if span.GetKind() != tracepb.Span_SPAN_KIND_SERVER {
+		t.Errorf("span kind = %v, want server", span.GetKind())
+	}
+
+	attrs := attributes(span)
+	if got, want := attrs["http.route"], "/v1/providers/{namespace}/{name}/versions"; got != want {
+		t.Errorf("http.route = %v, want %v", got, want)
+	}
+	if got, want := attrs["http.response.status_code"], int64(http.StatusNotFound); got != want {
+		t.Errorf("http.response.status_code = %v, want %v", got, want)
+	}
+	if got, want := attrs["url.path"], "/v1/providers/hashicorp/aws/versions"; got != want {
+		t.Errorf("url.path = %v, want %v", got, want)
+	}
+}
+
+func TestConfigureDisabledInstallsNoopProvider(t *testing.T) {
+	restoreGlobalProvider(t)
+
+	// A provider left over from an earlier configuration must be replaced
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	shutdown, err := Configure(context.Background(), config.RegistryConfig{}, "test")
+	if err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	defer func() { _ = shutdown(context.Background()) }()
+
+	_, span := tracer().Start(context.Background(), "test")
+	defer span.End()
+
+	if span.IsRecording() {
+		t.Error("span is recording with tracing disabled")
+	}
+	if span.SpanContext().IsValid() {
+		t.Error("span has a valid span context with tracing disabled")
+	}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type ProviderPackageParameters struct {
 	Namespace    string
 	Name         string
@@ -38,3 +40,123 @@ type APIParameters struct {
 type UserParameters struct {
 	Organization string
 }
+
+type ShareParameters struct {
+	Organization string
+	ResourceType string
+	Namespace    string
+	Name         string
+	Provider     string
+	Grantees     []string
+}
+
+type AuditEvent struct {
+	ID           string
+	Organization string
+	Actor        string
+	Action       string
+	Target       string
+	SourceIP     string
+	Outcome      string
+	StatusCode   int
+	Timestamp    time.Time
+}
+
+type AuditParameters struct {
+	Organization string
+	Since        *time.Time
+	Until        *time.Time
+	PageNumber   int
+	PageSize     int
+}
+
+type Webhook struct {
+	ID           string
+	Organization string
+	URL          string
+	Events       []string
+	Secret       string
+	Enabled      bool
+}
+
+type WebhookDelivery struct {
+	ID         string
+	WebhookID  string
+	EventID    string
+	EventType  string
+	Attempt    int
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	Success    bool
+	Timestamp  time.Time
+}
+
+type WebhookDeliveryParameters struct {
+	Organization string
+	WebhookID    string
+	PageNumber   int
+	PageSize     int
+}
+
+const (
+	DownloadKindProvider = "provider"
+	DownloadKindModule   = "module"
+)
+
+const (
+	DownloadDimensionTotal     = "total"
+	DownloadDimensionDay       = "day"
+	DownloadDimensionPlatform  = "platform"
+	DownloadDimensionTerraform = "terraform"
+)
+
+// Download is a single provider package or module download. Provider is the
+// module system and OS/Architecture are only set for providers.
+type Download struct {
+	Kind             string
+	Namespace        string
+	Name             string
+	Provider         string
+	Version          string
+	OS               string
+	Architecture     string
+	TerraformVersion string
+	Timestamp        time.Time
+}
+
+// DownloadCounter counts the downloads of a version along one dimension, Key
+// is the day, platform or Terraform version and empty for the total.
+type DownloadCounter struct {
+	Version   string
+	Dimension string
+	Key       string
+	Count     int64
+}
+
+type DownloadParameters struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Provider  string
+}
+
+// RateLimit allows Burst requests at once, refilled at Rate requests per
+// second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitBucket is the token bucket of one client and budget.
+type RateLimitBucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// RateLimitDecision tells whether a request may proceed, and otherwise when a
+// token will be available again.
+type RateLimitDecision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// NewClient returns the HTTP client used for deliveries. It refuses to
+// connect to loopback, private, link-local and unspecified addresses, checked
+// on the resolved address of every connection so DNS cannot be used to get
+// around it, and it does not follow redirects.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("webhook address %s: %w", address, err)
+			}
+			if !AllowedAddress(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not allowed", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// AllowedAddress reports whether webhooks may be delivered to the address.
+func AllowedAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestAllowedAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"203.0.113.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := AllowedAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("AllowedAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook reached a loopback server")
+	}))
+	defer server.Close()
+
+	resp, err := NewClient().Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Post() to a loopback address succeeded")
+	}
+	if !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Post() error = %v, want the address to be refused", err)
+	}
+}
+
+func TestClientDoesNotFollowRedirects(t *testing.T) {
+	client := NewClient()
+	if err := client.CheckRedirect(httptest.NewRequest(http.MethodGet, "http://example.com", nil), nil); err != http.ErrUseLastResponse {
+		t.Errorf("CheckRedirect() = %v, want http.ErrUseLastResponse", err)
+	}
+}
@@ -0,0 +1,194 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-terraform-registry/internal/backend"
+	"go-terraform-registry/internal/config"
+	"go-terraform-registry/internal/events"
+	registrytypes "go-terraform-registry/internal/types"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	EventHeader     = "X-Registry-Event"
+	DeliveryHeader  = "X-Registry-Delivery"
+	SignatureHeader = "X-Registry-Signature"
+
+	queueSize = 1000
+	baseDelay = time.Second
+	maxDelay  = 5 * time.Minute
+)
+
+// Dispatcher delivers registry events to the webhooks subscribed to them.
+// Every attempt is recorded in the delivery log of the webhook, failed
+// attempts are retried with exponential backoff. Timeouts and attempts are
+// taken from the configuration in effect when a delivery starts.
+type Dispatcher struct {
+	Backend backend.WebhooksBackend
+	Client  *http.Client
+	Config  *config.Reloader
+
+	queue chan events.Event
+	slots chan struct{}
+}
+
+func NewDispatcher(live *config.Reloader, b backend.WebhooksBackend) *Dispatcher {
+	return &Dispatcher{
+		Backend: b,
+		Client:  NewClient(),
+		Config:  live,
+		queue:   make(chan events.Event, queueSize),
+		slots:   make(chan struct{}, live.Current().WebhookWorkers),
+	}
+}
+
+// Start dispatches queued events until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-d.queue:
+				d.dispatch(ctx, event)
+			}
+		}
+	}()
+}
+
+// Handle queues an event for delivery. It is meant to be subscribed to the
+// event bus and never blocks the publisher.
+func (d *Dispatcher) Handle(event events.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		slog.Warn("Webhook queue full, dropping event", "id", event.ID, "type", event.Type)
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event events.Event) {
+	webhooks, err := d.Backend.WebhooksSubscribed(ctx, event.Organization)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing webhooks", "organization", event.Organization, "error", err)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding event", "id", event.ID, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !events.Matches(webhook.Events, event.Type) {
+			continue
+		}
+
+		go d.deliver(ctx, webhook, event, payload)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, webhook registrytypes.Webhook, event events.Event, payload []byte) {
+	c := d.Config.Current()
+
+	delay := baseDelay
+	for attempt := 1; attempt <= c.WebhookMaxAttempts; attempt++ {
+		if d.attempt(ctx, webhook, event, payload, attempt, c.WebhookTimeout) {
+			return
+		}
+
+		if attempt == c.WebhookMaxAttempts {
+			slog.WarnContext(ctx, "Webhook gave up on event", "webhook", webhook.ID, "event", event.ID, "attempts", attempt)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxDelay)
+	}
+}
+
+// attempt posts the event once and records the outcome. The slots bound the
+// number of requests in flight, backoff waits do not hold one.
+func (d *Dispatcher) attempt(ctx context.Context, webhook registrytypes.Webhook, event events.Event, payload []byte, attempt int, timeout time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case d.slots <- struct{}{}:
+	}
+	defer func() {
+		<-d.slots
+	}()
+
+	delivery := registrytypes.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Attempt:   attempt,
+		Timestamp: time.Now().UTC(),
+	}
+
+	statusCode, err := d.post(ctx, webhook, event, payload, timeout)
+	delivery.Duration = time.Since(delivery.Timestamp)
+	delivery.StatusCode = statusCode
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.Success = err == nil
+
+	// Recorded even when the registry is shutting down
+	if err := d.Backend.WebhookDeliveriesCreate(context.WithoutCancel(ctx), delivery); err != nil {
+		slog.ErrorContext(ctx, "Error recording webhook delivery", "webhook", webhook.ID, "event", event.ID, "error", err)
+	}
+
+	return delivery.Success
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook registrytypes.Webhook, event events.Event, payload []byte, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, payload))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value of body, the hex encoded
+// HMAC-SHA256 with the webhook secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS namespaces
+(
+  namespace_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization varchar(64) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  public BOOLEAN NOT NULL DEFAULT false,
+  public_modules JSONB NOT NULL DEFAULT '[]',
+  public_providers JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT unique_namespace_identity UNIQUE (organization, namespace)
+);
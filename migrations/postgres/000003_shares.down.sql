@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shares_resource;
+
+DROP TABLE IF EXISTS shares;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS shares
+(
+  share_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization varchar(64) NOT NULL,
+  grantee varchar(64) NOT NULL,
+  resource_type varchar(32) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  provider varchar(64) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT unique_share_identity UNIQUE (organization, grantee, resource_type, namespace, name, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_resource ON shares (lower(organization), resource_type, lower(namespace), lower(name));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_organizations_name;
+
+DROP TABLE IF EXISTS organizations;
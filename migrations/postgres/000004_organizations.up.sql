@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+  organization_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  name varchar(64) NOT NULL,
+  email varchar(256) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT unique_organization_name UNIQUE (name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_name ON organizations (lower(name));
@@ -0,0 +1,11 @@
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS default_registry varchar(64) NOT NULL DEFAULT 'private';
+
+INSERT INTO organizations (name)
+  SELECT DISTINCT ON (lower(o.organization)) o.organization
+  FROM (
+    SELECT organization FROM providers
+    UNION SELECT organization FROM modules
+    UNION SELECT organization FROM namespaces
+    UNION SELECT organization FROM shares
+  ) o
+ON CONFLICT DO NOTHING;
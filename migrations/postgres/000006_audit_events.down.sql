@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_events_organization;
+
+DROP TABLE IF EXISTS audit_events;
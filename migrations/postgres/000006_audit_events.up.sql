@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS audit_events
+(
+  audit_event_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization varchar(64) NOT NULL,
+  actor varchar(256) NOT NULL,
+  action varchar(64) NOT NULL,
+  target varchar(1024) NOT NULL,
+  source_ip varchar(64) NOT NULL,
+  outcome varchar(16) NOT NULL,
+  status_code integer NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_organization ON audit_events (lower(organization), created_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_events_sequence;
+
+ALTER TABLE audit_events DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE audit_events DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,5 @@
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS sequence bigint;
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS prev_hash varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS hash varchar(64) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_events_sequence ON audit_events (lower(organization), sequence);
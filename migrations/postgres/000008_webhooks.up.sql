@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+  webhook_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization varchar(64) NOT NULL,
+  url varchar(2048) NOT NULL,
+  events JSONB NOT NULL DEFAULT '[]',
+  secret varchar(256) NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_organization ON webhooks (lower(organization));
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+  delivery_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  webhook_id uuid NOT NULL,
+  event_id varchar(64) NOT NULL,
+  event_type varchar(64) NOT NULL,
+  attempt integer NOT NULL,
+  status_code integer NOT NULL,
+  error text NOT NULL DEFAULT '',
+  duration_ms bigint NOT NULL,
+  success BOOLEAN NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(webhook_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_registry_events_organization;
+
+DROP TABLE IF EXISTS registry_events;
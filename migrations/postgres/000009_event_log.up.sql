@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS registry_events
+(
+  sequence bigserial PRIMARY KEY,
+  event_id varchar(64) NOT NULL UNIQUE,
+  organization varchar(64) NOT NULL,
+  event_type varchar(64) NOT NULL,
+  subject varchar(512) NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_registry_events_organization ON registry_events (lower(organization), sequence);
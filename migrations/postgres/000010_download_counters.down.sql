@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_download_counters_counter;
+
+DROP TABLE IF EXISTS download_counters;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS download_counters
+(
+  kind varchar(16) NOT NULL,
+  namespace varchar(64) NOT NULL,
+  name varchar(128) NOT NULL,
+  provider varchar(64) NOT NULL DEFAULT '',
+  version varchar(64) NOT NULL,
+  dimension varchar(16) NOT NULL,
+  key varchar(64) NOT NULL DEFAULT '',
+  count bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_download_counters_counter ON download_counters (kind, lower(namespace), lower(name), lower(provider), version, dimension, key);